@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AcceptType is a media range of an Accept header.
+type AcceptType struct {
+	// MediaType is the lower case media range, e.g. text/html, text/* or */*
+	MediaType string
+	// Quality is the q parameter, 1 if not set
+	Quality float64
+	// Params are the parameters of the media range except q
+	Params map[string]string
+}
+
+// specificity returns 2 for type/subtype, 1 for type/* and 0 for */*.
+func (a AcceptType) specificity() int {
+	switch {
+	case a.MediaType == "*/*":
+		return 0
+	case strings.HasSuffix(a.MediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matches returns true if the media range includes the media type.
+func (a AcceptType) matches(mediaType string) bool {
+	switch a.specificity() {
+	case 0:
+		return true
+	case 1:
+		mainType, _, _ := strings.Cut(mediaType, "/")
+		return a.MediaType == mainType+"/*"
+	default:
+		return a.MediaType == mediaType
+	}
+}
+
+// ParseAccept parses the Accept header and returns the media ranges sorted by quality,
+// more specific ranges first on equal quality. Invalid ranges are skipped.
+func ParseAccept(header string) []AcceptType {
+	var result []AcceptType
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if !strings.Contains(mediaType, "/") {
+			continue
+		}
+		acceptType := AcceptType{
+			MediaType: mediaType,
+			Quality:   1,
+		}
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok {
+				continue
+			}
+			key = strings.ToLower(strings.TrimSpace(key))
+			value = strings.TrimSpace(value)
+			if key == "q" {
+				if quality, err := strconv.ParseFloat(value, 64); err == nil {
+					acceptType.Quality = quality
+				}
+				continue
+			}
+			if acceptType.Params == nil {
+				acceptType.Params = make(map[string]string)
+			}
+			acceptType.Params[key] = strings.Trim(value, `"`)
+		}
+		result = append(result, acceptType)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Quality != result[j].Quality {
+			return result[i].Quality > result[j].Quality
+		}
+		return result[i].specificity() > result[j].specificity()
+	})
+	return result
+}
+
+// NegotiateContentType returns the offered media type with the highest quality in the Accept header.
+// On equal quality the type matched more specifically wins, then the type offered first.
+// It returns the first offered type if the header is empty and an empty string if none is acceptable.
+func NegotiateContentType(header string, offered []string) string {
+	acceptTypes := ParseAccept(header)
+	if len(acceptTypes) == 0 {
+		if len(offered) == 0 {
+			return ""
+		}
+		return offered[0]
+	}
+	result := ""
+	bestQuality := 0.0
+	bestSpecificity := -1
+	for _, mediaType := range offered {
+		quality, specificity := acceptQuality(acceptTypes, strings.ToLower(mediaType))
+		if quality <= 0 {
+			continue
+		}
+		if quality > bestQuality || quality == bestQuality && specificity > bestSpecificity {
+			result = mediaType
+			bestQuality = quality
+			bestSpecificity = specificity
+		}
+	}
+	return result
+}
+
+// acceptQuality returns the quality and specificity of the most specific media range
+// matching the media type, or -1 as specificity if none matches.
+func acceptQuality(acceptTypes []AcceptType, mediaType string) (float64, int) {
+	quality := 0.0
+	specificity := -1
+	for _, acceptType := range acceptTypes {
+		if !acceptType.matches(mediaType) || acceptType.specificity() <= specificity {
+			continue
+		}
+		quality = acceptType.Quality
+		specificity = acceptType.specificity()
+	}
+	return quality, specificity
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Accept", func() {
+	const header = "text/html,application/json;q=0.9,*/*;q=0.1"
+	It("parses media types sorted by quality", func() {
+		Expect(libhttp.ParseAccept("*/*;q=0.1, application/json;q=0.9, text/html")).To(Equal([]libhttp.AcceptType{
+			{MediaType: "text/html", Quality: 1},
+			{MediaType: "application/json", Quality: 0.9},
+			{MediaType: "*/*", Quality: 0.1},
+		}))
+	})
+	It("sorts more specific types first on equal quality", func() {
+		Expect(libhttp.ParseAccept("*/*, text/*, text/html;level=1")).To(Equal([]libhttp.AcceptType{
+			{MediaType: "text/html", Quality: 1, Params: map[string]string{"level": "1"}},
+			{MediaType: "text/*", Quality: 1},
+			{MediaType: "*/*", Quality: 1},
+		}))
+	})
+	It("skips invalid media types", func() {
+		Expect(libhttp.ParseAccept("invalid, , text/plain")).To(Equal([]libhttp.AcceptType{
+			{MediaType: "text/plain", Quality: 1},
+		}))
+	})
+	DescribeTable("NegotiateContentType",
+		func(header string, offered []string, expected string) {
+			Expect(libhttp.NegotiateContentType(header, offered)).To(Equal(expected))
+		},
+		Entry("highest quality", header, []string{"application/json", "text/html"}, "text/html"),
+		Entry("lower quality", header, []string{"application/json", "text/plain"}, "application/json"),
+		Entry("wildcard fallback", header, []string{"text/plain", "application/xml"}, "text/plain"),
+		Entry("specific over wildcard", "*/*, application/json", []string{"text/plain", "application/json"}, "application/json"),
+		Entry("subtype wildcard", "text/*;q=0.5, */*;q=0.1", []string{"application/json", "text/plain"}, "text/plain"),
+		Entry("not acceptable", "text/html, application/json;q=0", []string{"application/json"}, ""),
+		Entry("no match", "text/html", []string{"application/json"}, ""),
+		Entry("empty header", "", []string{"application/json", "text/plain"}, "application/json"),
+		Entry("nothing offered", header, nil, ""),
+		Entry("case insensitive", "Application/JSON", []string{"application/json"}, "application/json"),
+	)
+})
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// NewAccessLogHandler writes a line per request in combined log format followed by the duration in milliseconds:
+//
+//	127.0.0.1 - - [16/Oct/2026:12:00:00 +0000] "GET /path?a=b HTTP/1.1" 200 42 "referer" "user-agent" 3
+//
+// Credentials in query parameters like hapikey, passphrase, confirm, password or token are redacted.
+// Each line is written with a single Write call guarded by a mutex, so the writer does not need to be safe for concurrent use.
+func NewAccessLogHandler(next http.Handler, writer io.Writer) http.Handler {
+	var mux sync.Mutex
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := NewResponseRecorder(resp)
+		next.ServeHTTP(recorder, req)
+		line := fmt.Sprintf(
+			"%s - - [%s] \"%s %s %s\" %d %d %q %q %d\n",
+			remoteHost(req),
+			start.Format("02/Jan/2006:15:04:05 -0700"),
+			req.Method,
+			removeSensibleArgs(req.URL.RequestURI()),
+			req.Proto,
+			recorder.StatusCode(),
+			recorder.BytesWritten(),
+			req.Referer(),
+			req.UserAgent(),
+			time.Since(start).Milliseconds(),
+		)
+		mux.Lock()
+		defer mux.Unlock()
+		if _, err := io.WriteString(writer, line); err != nil {
+			glog.Warningf("write access log failed: %v", err)
+		}
+	})
+}
+
+func remoteHost(req *http.Request) string {
+	if ip := parseIP(req.RemoteAddr); ip != nil {
+		return ip.String()
+	}
+	return req.RemoteAddr
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccessLogHandler", func() {
+	var logBuf *bytes.Buffer
+	var handler http.Handler
+	BeforeEach(func() {
+		logBuf = &bytes.Buffer{}
+		handler = libhttp.NewAccessLogHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/fail" {
+				http.Error(resp, "failed", http.StatusInternalServerError)
+				return
+			}
+			_, _ = resp.Write([]byte("hello"))
+		}), logBuf)
+	})
+	serve := func(target string) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = "10.1.2.3:4567"
+		req.Header.Set("User-Agent", "test-agent")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	It("logs a successful request", func() {
+		serve("/hello?name=world")
+		Expect(logBuf.String()).To(MatchRegexp(`^10\.1\.2\.3 - - \[[^\]]+\] "GET /hello\?name=world HTTP/1\.1" 200 5 "" "test-agent" \d+\n$`))
+	})
+	It("writes concurrent requests to a writer that is not safe for concurrent use", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				serve("/hello")
+			}()
+		}
+		wg.Wait()
+		lines := strings.Split(strings.TrimSuffix(logBuf.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(20))
+		for _, line := range lines {
+			Expect(line).To(MatchRegexp(`^10\.1\.2\.3 - - \[[^\]]+\] "GET /hello HTTP/1\.1" 200 5 "" "test-agent" \d+$`))
+		}
+	})
+	It("logs a failed request", func() {
+		serve("/fail")
+		Expect(logBuf.String()).To(MatchRegexp(`"GET /fail HTTP/1\.1" 500 7 `))
+	})
+	It("redacts sensitive query parameters", func() {
+		serve("/hello?hapikey=secret&name=world")
+		Expect(logBuf.String()).To(ContainSubstring(`"GET /hello?hapikey=***&name=world HTTP/1.1"`))
+		Expect(logBuf.String()).NotTo(ContainSubstring("secret"))
+	})
+	It("redacts the passphrase and confirmation of dangerous handlers", func() {
+		serve("/debug/pprof/heap?passphrase=abc123&Confirm=prod-db&name=world&mytoken=visible")
+		Expect(logBuf.String()).To(ContainSubstring(`"GET /debug/pprof/heap?passphrase=***&Confirm=***&name=world&mytoken=visible HTTP/1.1"`))
+		Expect(logBuf.String()).NotTo(ContainSubstring("abc123"))
+		Expect(logBuf.String()).NotTo(ContainSubstring("prod-db"))
+	})
+})
@@ -7,27 +7,163 @@ package http
 import (
 	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/bborbe/run"
+	libtime "github.com/bborbe/time"
 	"github.com/golang/glog"
 )
 
-func NewBackgroundRunHandler(ctx context.Context, runFunc run.Func) http.Handler {
-	parallelSkipper := run.NewParallelSkipper()
-	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
-		go func() {
-			action := parallelSkipper.SkipParallel(func(ctx context.Context) error {
-				if err := runFunc(ctx); err != nil {
-					return err
-				}
-				return nil
-			})
-			glog.V(2).Infof("run started")
-			if err := action(ctx); err != nil {
-				glog.V(1).Infof("run failed: %v", err)
-			}
-			glog.V(2).Infof("run completed")
-		}()
-		_, _ = WriteAndGlog(resp, "run triggered. Check logs for progress.")
-	})
+// BackgroundRunHandler triggers a run in the background and responds with 202 Accepted.
+// Triggers while a run is in progress are skipped with 409 Conflict.
+type BackgroundRunHandler interface {
+	http.Handler
+	// IsRunning returns true while a run is in progress
+	IsRunning() bool
+}
+
+// BackgroundRunHandlerOptions configures a BackgroundRunHandler.
+type BackgroundRunHandlerOptions struct {
+	// OnError is called with the error of a failed run, e.g. to report it to Sentry
+	OnError func(err error)
+	// MinInterval skips triggers with 429 Too Many Requests if the previous run finished less than MinInterval ago
+	MinInterval time.Duration
+	// CurrentDateTime is used to check MinInterval, defaults to the system clock
+	CurrentDateTime libtime.CurrentDateTimeGetter
+}
+
+// WithBackgroundRunErrorHandler calls onError with the error of a failed run.
+func WithBackgroundRunErrorHandler(onError func(err error)) func(*BackgroundRunHandlerOptions) {
+	return func(options *BackgroundRunHandlerOptions) {
+		options.OnError = onError
+	}
+}
+
+// WithBackgroundRunMinInterval skips triggers with 429 Too Many Requests
+// if the previous run finished less than minInterval ago.
+func WithBackgroundRunMinInterval(minInterval time.Duration) func(*BackgroundRunHandlerOptions) {
+	return func(options *BackgroundRunHandlerOptions) {
+		options.MinInterval = minInterval
+	}
+}
+
+// WithBackgroundRunCurrentDateTime sets the clock used to check the min interval.
+func WithBackgroundRunCurrentDateTime(currentDateTime libtime.CurrentDateTimeGetter) func(*BackgroundRunHandlerOptions) {
+	return func(options *BackgroundRunHandlerOptions) {
+		options.CurrentDateTime = currentDateTime
+	}
+}
+
+// NewBackgroundRunHandler returns a handler executing runFunc with ctx in the background.
+// The returned handler is a BackgroundRunHandler.
+func NewBackgroundRunHandler(ctx context.Context, runFunc run.Func, optionFns ...func(*BackgroundRunHandlerOptions)) http.Handler {
+	return newBackgroundRunHandler(ctx, runFunc, optionFns...)
+}
+
+// NewBackgroundRunHandlerWithErrorHandler is like NewBackgroundRunHandler,
+// but calls onError with the error of a failed run, e.g. to report it to Sentry.
+func NewBackgroundRunHandlerWithErrorHandler(ctx context.Context, runFunc run.Func, onError func(err error), optionFns ...func(*BackgroundRunHandlerOptions)) BackgroundRunHandler {
+	optionFns = append([]func(*BackgroundRunHandlerOptions){
+		WithBackgroundRunErrorHandler(onError),
+	}, optionFns...)
+	return newBackgroundRunHandler(ctx, runFunc, optionFns...)
+}
+
+// NewBackgroundRunHandlerWithMinInterval is like NewBackgroundRunHandler,
+// but skips triggers with 429 Too Many Requests if the previous run finished less than minInterval ago.
+func NewBackgroundRunHandlerWithMinInterval(ctx context.Context, runFunc run.Func, minInterval time.Duration, currentDateTime libtime.CurrentDateTimeGetter, optionFns ...func(*BackgroundRunHandlerOptions)) BackgroundRunHandler {
+	optionFns = append([]func(*BackgroundRunHandlerOptions){
+		WithBackgroundRunMinInterval(minInterval),
+		WithBackgroundRunCurrentDateTime(currentDateTime),
+	}, optionFns...)
+	return newBackgroundRunHandler(ctx, runFunc, optionFns...)
+}
+
+func newBackgroundRunHandler(ctx context.Context, runFunc run.Func, optionFns ...func(*BackgroundRunHandlerOptions)) *backgroundRunHandler {
+	options := BackgroundRunHandlerOptions{}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return &backgroundRunHandler{
+		ctx:             ctx,
+		runFunc:         runFunc,
+		onError:         options.OnError,
+		minInterval:     options.MinInterval,
+		currentDateTime: options.CurrentDateTime,
+	}
+}
+
+type backgroundRunHandler struct {
+	ctx             context.Context
+	runFunc         run.Func
+	onError         func(err error)
+	minInterval     time.Duration
+	currentDateTime libtime.CurrentDateTimeGetter
+
+	mux          sync.Mutex
+	running      bool
+	lastFinished time.Time
+}
+
+func (b *backgroundRunHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if statusCode, ok := b.start(); !ok {
+		resp.WriteHeader(statusCode)
+		if statusCode == http.StatusTooManyRequests {
+			_, _ = WriteAndGlog(resp, "previous run finished less than %v ago. Trigger skipped.", b.minInterval)
+			return
+		}
+		_, _ = WriteAndGlog(resp, "run already running. Trigger skipped.")
+		return
+	}
+	go b.run()
+	resp.WriteHeader(http.StatusAccepted)
+	_, _ = WriteAndGlog(resp, "run triggered. Check logs for progress.")
+}
+
+func (b *backgroundRunHandler) IsRunning() bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.running
+}
+
+// start marks the handler as running and returns the status code to respond if the run must be skipped.
+func (b *backgroundRunHandler) start() (int, bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.running {
+		return http.StatusConflict, false
+	}
+	if b.minInterval > 0 && !b.lastFinished.IsZero() && b.now().Sub(b.lastFinished) < b.minInterval {
+		return http.StatusTooManyRequests, false
+	}
+	b.running = true
+	return 0, true
+}
+
+func (b *backgroundRunHandler) finish() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.running = false
+	b.lastFinished = b.now()
+}
+
+func (b *backgroundRunHandler) now() time.Time {
+	if b.currentDateTime == nil {
+		return time.Now()
+	}
+	return time.Time(b.currentDateTime.Now())
+}
+
+func (b *backgroundRunHandler) run() {
+	defer b.finish()
+	glog.V(2).Infof("run started")
+	if err := b.runFunc(b.ctx); err != nil {
+		glog.V(1).Infof("run failed: %v", err)
+		if b.onError != nil {
+			b.onError(err)
+		}
+		return
+	}
+	glog.V(2).Infof("run completed")
 }
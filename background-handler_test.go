@@ -6,12 +6,15 @@ package http_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	libhttp "github.com/bborbe/http"
+	libtime "github.com/bborbe/time"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -45,6 +48,9 @@ var _ = Describe("Background Run Handler", func() {
 		It("calls func", func() {
 			Expect(counter).To(Equal(1))
 		})
+		It("returns accepted", func() {
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+		})
 	})
 	Context("concurrent call", func() {
 		var response1 *httptest.ResponseRecorder
@@ -67,5 +73,116 @@ var _ = Describe("Background Run Handler", func() {
 		It("calls func", func() {
 			Expect(counter).To(Equal(1))
 		})
+		It("returns accepted for the first trigger", func() {
+			Expect(response1.Code).To(Equal(http.StatusAccepted))
+		})
+		It("returns conflict for the duplicate trigger", func() {
+			Expect(response2.Code).To(Equal(http.StatusConflict))
+			Expect(response2.Body.String()).To(ContainSubstring("already running"))
+		})
+	})
+	Context("with error handler", func() {
+		var handler libhttp.BackgroundRunHandler
+		var errs chan error
+		var release chan struct{}
+		var runs int32
+		BeforeEach(func() {
+			errs = make(chan error, 10)
+			release = make(chan struct{})
+			runs = 0
+			handler = libhttp.NewBackgroundRunHandlerWithErrorHandler(ctx, func(ctx context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				<-release
+				return errors.New("banana")
+			}, func(err error) {
+				errs <- err
+			})
+		})
+		It("calls onError exactly once", func() {
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			close(release)
+			Eventually(errs).Should(Receive(MatchError("banana")))
+			Consistently(errs, 50*time.Millisecond).ShouldNot(Receive())
+		})
+		It("runs only one instance for concurrent triggers", func() {
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			Expect(handler.IsRunning()).To(BeTrue())
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			close(release)
+			Eventually(errs).Should(Receive())
+			Eventually(handler.IsRunning).Should(BeFalse())
+			Expect(atomic.LoadInt32(&runs)).To(Equal(int32(1)))
+			Expect(errs).NotTo(Receive())
+		})
+	})
+	Context("with min interval", func() {
+		var handler libhttp.BackgroundRunHandler
+		var currentDateTime libtime.CurrentDateTime
+		var runs int32
+		BeforeEach(func() {
+			runs = 0
+			currentDateTime = libtime.NewCurrentDateTime()
+			currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+			handler = libhttp.NewBackgroundRunHandlerWithMinInterval(
+				ctx,
+				func(ctx context.Context) error {
+					atomic.AddInt32(&runs, 1)
+					return nil
+				},
+				time.Hour,
+				currentDateTime,
+			)
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+			Eventually(handler.IsRunning).Should(BeFalse())
+		})
+		It("rejects a trigger within the interval", func() {
+			currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 12, 59, 0, 0, time.UTC)))
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			Expect(response.Code).To(Equal(http.StatusTooManyRequests))
+			Consistently(func() int32 { return atomic.LoadInt32(&runs) }, 50*time.Millisecond).Should(Equal(int32(1)))
+		})
+		It("accepts a trigger after the interval", func() {
+			currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)))
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+			Eventually(func() int32 { return atomic.LoadInt32(&runs) }).Should(Equal(int32(2)))
+		})
+	})
+	Context("with error handler and min interval", func() {
+		var handler libhttp.BackgroundRunHandler
+		var errs chan error
+		var currentDateTime libtime.CurrentDateTime
+		BeforeEach(func() {
+			errs = make(chan error, 10)
+			currentDateTime = libtime.NewCurrentDateTime()
+			currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+			handler = libhttp.NewBackgroundRunHandler(
+				ctx,
+				func(ctx context.Context) error {
+					return errors.New("banana")
+				},
+				libhttp.WithBackgroundRunErrorHandler(func(err error) {
+					errs <- err
+				}),
+				libhttp.WithBackgroundRunMinInterval(time.Hour),
+				libhttp.WithBackgroundRunCurrentDateTime(currentDateTime),
+			).(libhttp.BackgroundRunHandler)
+		})
+		It("reports the error and rejects a trigger within the interval", func() {
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			Expect(response.Code).To(Equal(http.StatusAccepted))
+			Eventually(errs).Should(Receive(MatchError("banana")))
+			Eventually(handler.IsRunning).Should(BeFalse())
+
+			response = httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			Expect(response.Code).To(Equal(http.StatusTooManyRequests))
+		})
 	})
 })
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// NewBasicAuthHandler passes requests with Basic Auth credentials accepted by verify to next.
+// Other requests get 401 Unauthorized with a WWW-Authenticate challenge for the realm.
+func NewBasicAuthHandler(next http.Handler, realm string, verify func(user, pass string) bool) http.Handler {
+	challenge := `Basic realm="` + strings.ReplaceAll(realm, `"`, `\"`) + `", charset="UTF-8"`
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		user, pass, ok := req.BasicAuth()
+		if !ok || !verify(user, pass) {
+			glog.V(2).Infof("%s request to %s unauthorized", req.Method, req.URL.Path)
+			resp.Header().Set("WWW-Authenticate", challenge)
+			http.Error(resp, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// NewStaticBasicAuthVerifier returns a verify func for NewBasicAuthHandler that accepts a single user.
+// The credentials are compared in constant time.
+func NewStaticBasicAuthVerifier(username string, password string) func(user, pass string) bool {
+	expectedUser := sha256.Sum256([]byte(username))
+	expectedPass := sha256.Sum256([]byte(password))
+	return func(user, pass string) bool {
+		actualUser := sha256.Sum256([]byte(user))
+		actualPass := sha256.Sum256([]byte(pass))
+		userMatch := subtle.ConstantTimeCompare(expectedUser[:], actualUser[:])
+		passMatch := subtle.ConstantTimeCompare(expectedPass[:], actualPass[:])
+		return userMatch&passMatch == 1
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BasicAuthHandler", func() {
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	BeforeEach(func() {
+		req = httptest.NewRequest(http.MethodGet, "/admin", nil)
+	})
+	JustBeforeEach(func() {
+		resp = httptest.NewRecorder()
+		libhttp.NewBasicAuthHandler(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				_, _ = resp.Write([]byte("secret"))
+			}),
+			"admin",
+			libhttp.NewStaticBasicAuthVerifier("user", "pass"),
+		).ServeHTTP(resp, req)
+	})
+	Context("missing header", func() {
+		It("returns 401 with challenge", func() {
+			Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+			Expect(resp.Header().Get("WWW-Authenticate")).To(Equal(`Basic realm="admin", charset="UTF-8"`))
+			Expect(resp.Body.String()).NotTo(ContainSubstring("secret"))
+		})
+	})
+	Context("wrong credentials", func() {
+		BeforeEach(func() {
+			req.SetBasicAuth("user", "wrong")
+		})
+		It("returns 401", func() {
+			Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+			Expect(resp.Body.String()).NotTo(ContainSubstring("secret"))
+		})
+	})
+	Context("correct credentials", func() {
+		BeforeEach(func() {
+			req.SetBasicAuth("user", "pass")
+		})
+		It("passes the request", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(Equal("secret"))
+		})
+	})
+})
+
+var _ = Describe("StaticBasicAuthVerifier", func() {
+	DescribeTable("verify",
+		func(user string, pass string, expected bool) {
+			Expect(libhttp.NewStaticBasicAuthVerifier("user", "pass")(user, pass)).To(Equal(expected))
+		},
+		Entry("match", "user", "pass", true),
+		Entry("wrong user", "other", "pass", false),
+		Entry("wrong pass", "user", "other", false),
+		Entry("empty", "", "", false),
+		Entry("prefix", "user", "pas", false),
+	)
+})
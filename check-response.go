@@ -27,6 +27,27 @@ func (r RequestFailedError) Error() string {
 
 var NotFound = stderrors.New("not found")
 
+var (
+	// ErrNotFound is returned wrapped for responses with status 404
+	ErrNotFound = NotFound
+	// ErrUnauthorized is returned wrapped for responses with status 401
+	ErrUnauthorized = stderrors.New("unauthorized")
+	// ErrForbidden is returned wrapped for responses with status 403
+	ErrForbidden = stderrors.New("forbidden")
+	// ErrTooManyRequests is returned wrapped for responses with status 429
+	ErrTooManyRequests = stderrors.New("too many requests")
+)
+
+var statusCodeErrors = map[int]error{
+	http.StatusNotFound:        ErrNotFound,
+	http.StatusUnauthorized:    ErrUnauthorized,
+	http.StatusForbidden:       ErrForbidden,
+	http.StatusTooManyRequests: ErrTooManyRequests,
+}
+
+// DefaultCheckResponseMaxBodyBytes is the maximum number of body bytes added to the error of a failed response.
+const DefaultCheckResponseMaxBodyBytes int64 = 8 << 10
+
 func addRequestResponseToError(err error, resp *http.Response, req *http.Request) error {
 	data := make(map[string]string)
 	if req != nil {
@@ -43,40 +64,91 @@ func addRequestResponseToError(err error, resp *http.Response, req *http.Request
 	)
 }
 
+// CheckResponseIsSuccessful returns an error if the status code is not 2xx or 3xx.
+// Status 401, 403, 404 and 429 return an error wrapping ErrUnauthorized, ErrForbidden, ErrNotFound and ErrTooManyRequests.
 func CheckResponseIsSuccessful(req *http.Request, resp *http.Response) error {
-	if resp.StatusCode == 404 {
-		return errors.Wrapf(
-			req.Context(),
-			NotFound,
-			"%s to %s failed with status %d",
-			req.Method,
-			req.URL.String(),
-			resp.StatusCode,
-		)
+	return CheckResponseIsSuccessfulWithMaxBodyBytes(req, resp, DefaultCheckResponseMaxBodyBytes)
+}
+
+// CheckResponseIsSuccessfulWithMaxBodyBytes is like CheckResponseIsSuccessful,
+// but adds at most maxBodyBytes of the body to the error.
+func CheckResponseIsSuccessfulWithMaxBodyBytes(req *http.Request, resp *http.Response, maxBodyBytes int64) error {
+	return checkResponse(req, resp, maxBodyBytes, func(statusCode int) bool {
+		return statusCode/100 == 2 || statusCode/100 == 3
+	})
+}
+
+// CheckResponseWithAcceptedCodes returns an error if the status code is not one of the accepted codes.
+// Not accepted status 401, 403, 404 and 429 return the errors of CheckResponseIsSuccessful.
+func CheckResponseWithAcceptedCodes(req *http.Request, resp *http.Response, accepted ...int) error {
+	return checkResponse(req, resp, DefaultCheckResponseMaxBodyBytes, func(statusCode int) bool {
+		for _, acceptedCode := range accepted {
+			if statusCode == acceptedCode {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func checkResponse(req *http.Request, resp *http.Response, maxBodyBytes int64, isAccepted func(statusCode int) bool) error {
+	if isAccepted(resp.StatusCode) {
+		return nil
 	}
-	if resp.StatusCode/100 != 2 && resp.StatusCode/100 != 3 {
-		content, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		resp.Body = io.NopCloser(bytes.NewBuffer(content))
-		return errors.AddDataToError(
+	if statusCodeError, ok := statusCodeErrors[resp.StatusCode]; ok {
+		return addRequestResponseToError(
 			errors.Wrapf(
 				req.Context(),
-				RequestFailedError{
-					Method:     req.Method,
-					URL:        req.URL.String(),
-					StatusCode: resp.StatusCode,
-				},
-				"request failed content: %s",
-				string(content),
+				statusCodeError,
+				"%s to %s failed with status %d",
+				req.Method,
+				req.URL.String(),
+				resp.StatusCode,
 			),
-			map[string]string{
-				"status_code": strconv.Itoa(resp.StatusCode),
-				"status":      resp.Status,
-				"method":      req.Method,
-				"url":         req.URL.String(),
-				"body":        string(content),
-			},
+			resp,
+			req,
 		)
 	}
-	return nil
+	content := peekBody(resp, maxBodyBytes)
+	return errors.AddDataToError(
+		errors.Wrapf(
+			req.Context(),
+			RequestFailedError{
+				Method:     req.Method,
+				URL:        req.URL.String(),
+				StatusCode: resp.StatusCode,
+			},
+			"request failed content: %s",
+			string(content),
+		),
+		map[string]string{
+			"status_code": strconv.Itoa(resp.StatusCode),
+			"status":      resp.Status,
+			"method":      req.Method,
+			"url":         req.URL.String(),
+			"body":        string(content),
+		},
+	)
+}
+
+// peekBody returns at most maxBodyBytes of the body and keeps the full body readable.
+// A truncated content is marked with a suffix.
+func peekBody(resp *http.Response, maxBodyBytes int64) string {
+	if resp.Body == nil {
+		return ""
+	}
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes+1))
+	resp.Body = &readCloser{
+		Reader: io.MultiReader(bytes.NewReader(content), resp.Body),
+		Closer: resp.Body,
+	}
+	if int64(len(content)) > maxBodyBytes {
+		return string(content[:maxBodyBytes]) + "...(truncated)"
+	}
+	return string(content)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
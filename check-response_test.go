@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	liberrors "github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckResponse", func() {
+	var req *http.Request
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+	})
+	newResponse := func(statusCode int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}
+	}
+	Context("CheckResponseIsSuccessful", func() {
+		It("accepts 200", func() {
+			Expect(libhttp.CheckResponseIsSuccessful(req, newResponse(http.StatusOK, ""))).To(Succeed())
+		})
+		It("accepts 302", func() {
+			Expect(libhttp.CheckResponseIsSuccessful(req, newResponse(http.StatusFound, ""))).To(Succeed())
+		})
+		It("returns not found", func() {
+			err := libhttp.CheckResponseIsSuccessful(req, newResponse(http.StatusNotFound, ""))
+			Expect(errors.Is(err, libhttp.NotFound)).To(BeTrue())
+		})
+		It("returns request failed", func() {
+			err := libhttp.CheckResponseIsSuccessful(req, newResponse(http.StatusInternalServerError, "boom"))
+			var requestFailedError libhttp.RequestFailedError
+			Expect(errors.As(err, &requestFailedError)).To(BeTrue())
+			Expect(requestFailedError.StatusCode).To(Equal(http.StatusInternalServerError))
+			Expect(err.Error()).To(ContainSubstring("boom"))
+		})
+	})
+	DescribeTable("returns typed errors",
+		func(statusCode int, expectedErr error) {
+			err := libhttp.CheckResponseIsSuccessful(req, newResponse(statusCode, ""))
+			Expect(errors.Is(err, expectedErr)).To(BeTrue())
+			Expect(liberrors.DataFromError(err)).To(HaveKeyWithValue("status_code", strconv.Itoa(statusCode)))
+		},
+		Entry("401", http.StatusUnauthorized, libhttp.ErrUnauthorized),
+		Entry("403", http.StatusForbidden, libhttp.ErrForbidden),
+		Entry("404", http.StatusNotFound, libhttp.ErrNotFound),
+		Entry("429", http.StatusTooManyRequests, libhttp.ErrTooManyRequests),
+	)
+	Context("CheckResponseWithAcceptedCodes", func() {
+		It("accepts 204", func() {
+			Expect(libhttp.CheckResponseWithAcceptedCodes(req, newResponse(http.StatusNoContent, ""), http.StatusOK, http.StatusNoContent)).To(Succeed())
+		})
+		It("accepts 200", func() {
+			Expect(libhttp.CheckResponseWithAcceptedCodes(req, newResponse(http.StatusOK, ""), http.StatusOK)).To(Succeed())
+		})
+		It("rejects 302", func() {
+			err := libhttp.CheckResponseWithAcceptedCodes(req, newResponse(http.StatusFound, ""), http.StatusOK)
+			var requestFailedError libhttp.RequestFailedError
+			Expect(errors.As(err, &requestFailedError)).To(BeTrue())
+			Expect(requestFailedError.StatusCode).To(Equal(http.StatusFound))
+		})
+		It("accepts 404 if listed", func() {
+			Expect(libhttp.CheckResponseWithAcceptedCodes(req, newResponse(http.StatusNotFound, ""), http.StatusNotFound)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("CheckResponse with large body", func() {
+	It("truncates the body in the error but keeps it readable", func() {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		body := strings.Repeat("a", 100) + strings.Repeat("b", 100000)
+		resp := &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+		err = libhttp.CheckResponseIsSuccessfulWithMaxBodyBytes(req, resp, 100)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring(strings.Repeat("a", 100) + "...(truncated)"))
+		Expect(err.Error()).NotTo(ContainSubstring("b"))
+
+		content, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal(body))
+	})
+	It("caps the body by default", func() {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		resp := &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(strings.NewReader(strings.Repeat("a", 1<<20))),
+		}
+		err = libhttp.CheckResponseIsSuccessful(req, resp)
+		Expect(len(err.Error())).To(BeNumerically("<", 20000))
+	})
+})
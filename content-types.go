@@ -4,7 +4,29 @@
 
 package http
 
+import (
+	"mime"
+	"strings"
+)
+
 const (
-	ApplicationJsonContentType = "application/json"
-	TextHtml                   = "text/html"
+	ApplicationJsonContentType        = "application/json"
+	ApplicationXMLContentType         = "application/xml"
+	ApplicationOctetStreamContentType = "application/octet-stream"
+	TextPlainContentType              = "text/plain"
+	TextHTMLContentType               = "text/html"
+	TextCSVContentType                = "text/csv"
+	FormURLEncodedContentType         = "application/x-www-form-urlencoded"
+
+	TextHtml = TextHTMLContentType
 )
+
+// IsContentType returns true if the media type of the Content-Type header value is want,
+// ignoring case and parameters like charset.
+func IsContentType(header string, want string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return false
+	}
+	return mediaType == strings.ToLower(want)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("IsContentType",
+	func(header string, want string, expected bool) {
+		Expect(libhttp.IsContentType(header, want)).To(Equal(expected))
+	},
+	Entry("exact", "application/json", libhttp.ApplicationJsonContentType, true),
+	Entry("with charset", "application/json; charset=utf-8", libhttp.ApplicationJsonContentType, true),
+	Entry("upper case", "Application/JSON", libhttp.ApplicationJsonContentType, true),
+	Entry("other type", "text/plain; charset=utf-8", libhttp.ApplicationJsonContentType, false),
+	Entry("suffix", "application/problem+json", libhttp.ApplicationJsonContentType, false),
+	Entry("prefix", "application/jsonx", libhttp.ApplicationJsonContentType, false),
+	Entry("empty", "", libhttp.ApplicationJsonContentType, false),
+	Entry("invalid", "application/json;;", libhttp.ApplicationJsonContentType, false),
+	Entry("xml", "application/xml", libhttp.ApplicationXMLContentType, true),
+	Entry("html", "text/html; charset=utf-8", libhttp.TextHTMLContentType, true),
+)
@@ -5,12 +5,20 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"os"
 	"runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
 )
 
+var cpuProfileMux sync.Mutex
+
 func NewCpuProfileStartHandler() WithError {
 	return WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
 		f, err := os.Create("cpu.pprof")
@@ -27,3 +35,44 @@ func NewCpuProfileStopHandler() WithError {
 		return nil
 	})
 }
+
+// MaxCpuProfileDuration is the longest duration NewCpuProfileHandler records a cpu profile.
+const MaxCpuProfileDuration = 5 * time.Minute
+
+// NewCpuProfileHandler records a cpu profile for the given duration and returns it as cpu.pprof download.
+// The duration is limited to MaxCpuProfileDuration. The profiling stops early if the request is canceled.
+// Concurrent profiling is rejected with 409 Conflict.
+func NewCpuProfileHandler(duration time.Duration) WithError {
+	if duration > MaxCpuProfileDuration {
+		duration = MaxCpuProfileDuration
+	}
+	return WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+		if !cpuProfileMux.TryLock() {
+			return WrapWithConflict(errors.Errorf(ctx, "cpu profiling already running"))
+		}
+		defer cpuProfileMux.Unlock()
+
+		buf := &bytes.Buffer{}
+		if err := pprof.StartCPUProfile(buf); err != nil {
+			return WrapWithConflict(errors.Wrapf(ctx, err, "start cpu profile failed"))
+		}
+		timer := time.NewTimer(duration)
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+		pprof.StopCPUProfile()
+		if err := ctx.Err(); err != nil {
+			return errors.Wrapf(ctx, err, "cpu profiling canceled")
+		}
+		return sendProfile(ctx, resp, "cpu.pprof", buf.Bytes())
+	})
+}
+
+// sendProfile writes the profile content as attachment download.
+func sendProfile(ctx context.Context, resp http.ResponseWriter, fileName string, content []byte) error {
+	resp.Header().Set(ContentDispositionHeaderName, contentDispositionAttachment(fileName))
+	resp.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	return SendResponse(ctx, resp, ApplicationOctetStreamContentType, content, http.StatusOK)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CPUProfileHandler", func() {
+	It("returns the cpu profile as download", func() {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/debug/cpu", nil)
+		libhttp.NewErrorHandler(libhttp.NewCpuProfileHandler(50*time.Millisecond)).ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationOctetStreamContentType))
+		Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(Equal(`attachment; filename="cpu.pprof"`))
+		Expect(resp.Body.Len()).To(BeNumerically(">", 0))
+	})
+	It("rejects profiling while another cpu profile is running", func() {
+		Expect(pprof.StartCPUProfile(io.Discard)).To(Succeed())
+		defer pprof.StopCPUProfile()
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/debug/cpu", nil)
+		libhttp.NewErrorHandler(libhttp.NewCpuProfileHandler(time.Millisecond)).ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusConflict))
+	})
+	It("stops profiling if the request is canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/debug/cpu", nil).WithContext(ctx)
+		libhttp.NewErrorHandler(libhttp.NewCpuProfileHandler(time.Hour)).ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+	})
+})
@@ -0,0 +1,238 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	libtime "github.com/bborbe/time"
+	"github.com/golang/glog"
+)
+
+const (
+	PassphraseParameterName   = "passphrase"
+	ConfirmationParameterName = "confirm"
+)
+
+// DangerousHandlerWrapperOptions configures the passphrase of a DangerousHandlerWrapper.
+type DangerousHandlerWrapperOptions struct {
+	// Expiry defines how long a generated passphrase is valid
+	Expiry time.Duration
+	// PassphraseBytes defines the number of random bytes of a generated passphrase
+	PassphraseBytes int
+	// PassphraseSink is called with every generated passphrase, e.g. to use it in integration tests
+	PassphraseSink PassphraseSink
+	// Confirmation if set must be sent as X-Danger-Confirmation header or confirm parameter in addition to the passphrase
+	Confirmation string
+}
+
+// WithDangerousExpiry defines how long a generated passphrase is valid.
+func WithDangerousExpiry(expiry time.Duration) func(*DangerousHandlerWrapperOptions) {
+	return func(options *DangerousHandlerWrapperOptions) {
+		options.Expiry = expiry
+	}
+}
+
+// WithDangerousPassphraseBytes defines the number of random bytes of a generated passphrase.
+func WithDangerousPassphraseBytes(passphraseBytes int) func(*DangerousHandlerWrapperOptions) {
+	return func(options *DangerousHandlerWrapperOptions) {
+		options.PassphraseBytes = passphraseBytes
+	}
+}
+
+// WithDangerousPassphraseSink calls sink with every generated passphrase.
+func WithDangerousPassphraseSink(sink PassphraseSink) func(*DangerousHandlerWrapperOptions) {
+	return func(options *DangerousHandlerWrapperOptions) {
+		options.PassphraseSink = sink
+	}
+}
+
+// WithDangerousConfirmation requires the confirmation to equal expected, e.g. the name of the affected resource.
+func WithDangerousConfirmation(expected string) func(*DangerousHandlerWrapperOptions) {
+	return func(options *DangerousHandlerWrapperOptions) {
+		options.Confirmation = expected
+	}
+}
+
+// PassphraseSink receives the passphrase generated for the given path.
+type PassphraseSink func(path string, passphrase string, expiresAt time.Time)
+
+// DefaultDangerousHandlerWrapperOptions returns a 12 byte passphrase valid for 5 minutes.
+func DefaultDangerousHandlerWrapperOptions() DangerousHandlerWrapperOptions {
+	return DangerousHandlerWrapperOptions{
+		Expiry:          5 * time.Minute,
+		PassphraseBytes: 12,
+	}
+}
+
+// NewDangerousHandlerWrapper protects a dangerous handler with a passphrase.
+// The first call generates a passphrase and writes it to the log,
+// the handler is only executed if the request contains the current passphrase.
+func NewDangerousHandlerWrapper(handler http.Handler, optionFns ...func(*DangerousHandlerWrapperOptions)) http.Handler {
+	return NewDangerousHandlerWrapperWithCurrentDateTime(handler, libtime.NewCurrentDateTime(), optionFns...)
+}
+
+// NewDangerousHandlerWrapperWithCurrentDateTime is NewDangerousHandlerWrapper with an injectable clock,
+// e.g. to test the passphrase expiry.
+func NewDangerousHandlerWrapperWithCurrentDateTime(handler http.Handler, currentDateTime libtime.CurrentDateTimeGetter, optionFns ...func(*DangerousHandlerWrapperOptions)) http.Handler {
+	options := DefaultDangerousHandlerWrapperOptions()
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return NewDangerousHandlerWrapperWithOptions(handler, currentDateTime, options)
+}
+
+// NewDangerousHandlerWrapperWithOptions allows to configure expiry and length of the passphrase.
+// Zero values fall back to DefaultDangerousHandlerWrapperOptions.
+func NewDangerousHandlerWrapperWithOptions(handler http.Handler, currentDateTime libtime.CurrentDateTimeGetter, options DangerousHandlerWrapperOptions) http.Handler {
+	defaultOptions := DefaultDangerousHandlerWrapperOptions()
+	if options.Expiry <= 0 {
+		options.Expiry = defaultOptions.Expiry
+	}
+	if options.PassphraseBytes <= 0 {
+		options.PassphraseBytes = defaultOptions.PassphraseBytes
+	}
+	return &dangerousHandlerWrapper{
+		handler:         handler,
+		currentDateTime: currentDateTime,
+		options:         options,
+	}
+}
+
+type dangerousHandlerWrapper struct {
+	handler         http.Handler
+	currentDateTime libtime.CurrentDateTimeGetter
+	options         DangerousHandlerWrapperOptions
+
+	mux        sync.Mutex
+	passphrase string
+	expiresAt  time.Time
+}
+
+func (w *dangerousHandlerWrapper) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	providedPassphrase := providedPassphrase(req)
+	if providedPassphrase == "" {
+		if _, err := w.getCurrentPassphrase(req.URL.Path); err != nil {
+			glog.Warningf("generate passphrase failed: %v", err)
+			http.Error(resp, "generate passphrase failed", http.StatusInternalServerError)
+			return
+		}
+		http.Error(
+			resp,
+			fmt.Sprintf(
+				"dangerous action! passphrase required. check the logs for the passphrase and retry with header %s: <passphrase> or ?%s=<passphrase> within %s",
+				DangerPassphraseHeaderName,
+				PassphraseParameterName,
+				formatDuration(w.options.Expiry),
+			),
+			http.StatusForbidden,
+		)
+		return
+	}
+	if !w.isValidPassphrase(providedPassphrase) {
+		glog.V(1).Infof("invalid passphrase for %s", req.URL.Path)
+		http.Error(resp, "invalid or expired passphrase", http.StatusForbidden)
+		return
+	}
+	if w.options.Confirmation != "" && !isValidConfirmation(providedConfirmation(req), w.options.Confirmation) {
+		glog.V(1).Infof("missing or mismatched confirmation for %s", req.URL.Path)
+		http.Error(
+			resp,
+			fmt.Sprintf(
+				"confirmation missing or mismatched. retry with header %s: <name of the affected resource> or ?%s=<name of the affected resource>",
+				DangerConfirmationHeaderName,
+				ConfirmationParameterName,
+			),
+			http.StatusForbidden,
+		)
+		return
+	}
+	w.handler.ServeHTTP(resp, req)
+}
+
+// providedPassphrase returns the passphrase of the request.
+// The header takes precedence over the query parameter, because it does not leak into access logs.
+func providedPassphrase(req *http.Request) string {
+	if passphrase := req.Header.Get(DangerPassphraseHeaderName); passphrase != "" {
+		return passphrase
+	}
+	return req.URL.Query().Get(PassphraseParameterName)
+}
+
+// providedConfirmation returns the confirmation of the request.
+// The body is not read, so the wrapped handler still gets the unconsumed body.
+func providedConfirmation(req *http.Request) string {
+	if confirmation := req.Header.Get(DangerConfirmationHeaderName); confirmation != "" {
+		return confirmation
+	}
+	return req.URL.Query().Get(ConfirmationParameterName)
+}
+
+// getCurrentPassphrase returns the current passphrase and generates a new one if none exists or it is expired.
+func (w *dangerousHandlerWrapper) getCurrentPassphrase(path string) (string, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	now := time.Time(w.currentDateTime.Now())
+	if w.passphrase != "" && now.Before(w.expiresAt) {
+		return w.passphrase, nil
+	}
+	passphrase, err := generatePassphrase(w.options.PassphraseBytes)
+	if err != nil {
+		return "", err
+	}
+	w.passphrase = passphrase
+	w.expiresAt = now.Add(w.options.Expiry)
+	glog.Warningf("passphrase for %s: %s (valid until %s)", path, w.passphrase, w.expiresAt.Format(time.RFC3339))
+	if w.options.PassphraseSink != nil {
+		w.options.PassphraseSink(path, w.passphrase, w.expiresAt)
+	}
+	return w.passphrase, nil
+}
+
+func (w *dangerousHandlerWrapper) isValidPassphrase(providedPassphrase string) bool {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.passphrase == "" {
+		return false
+	}
+	if !time.Time(w.currentDateTime.Now()).Before(w.expiresAt) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(providedPassphrase), []byte(w.passphrase)) == 1
+}
+
+func isValidConfirmation(provided string, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
+
+func generatePassphrase(length int) (string, error) {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// formatDuration formats whole minutes and seconds human readable, e.g. "5 minutes".
+func formatDuration(duration time.Duration) string {
+	switch {
+	case duration == time.Minute:
+		return "1 minute"
+	case duration%time.Minute == 0:
+		return fmt.Sprintf("%d minutes", duration/time.Minute)
+	case duration == time.Second:
+		return "1 second"
+	case duration%time.Second == 0:
+		return fmt.Sprintf("%d seconds", duration/time.Second)
+	default:
+		return duration.String()
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DangerousHandlerWrapper", func() {
+	var currentDateTime libtime.CurrentDateTime
+	var handler http.Handler
+	var counter int
+	var options libhttp.DangerousHandlerWrapperOptions
+	var passphrase string
+	var passphrasePath string
+	var passphraseExpiresAt time.Time
+	var sink libhttp.PassphraseSink
+	BeforeEach(func() {
+		counter = 0
+		passphrase = ""
+		sink = func(path string, pass string, expiresAt time.Time) {
+			passphrasePath = path
+			passphrase = pass
+			passphraseExpiresAt = expiresAt
+		}
+		currentDateTime = libtime.NewCurrentDateTime()
+		currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+		options = libhttp.DangerousHandlerWrapperOptions{
+			PassphraseSink: sink,
+		}
+	})
+	JustBeforeEach(func() {
+		handler = libhttp.NewDangerousHandlerWrapperWithOptions(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				counter++
+				fmt.Fprint(resp, "done")
+			}),
+			currentDateTime,
+			options,
+		)
+	})
+	serveWithHeader := func(target string, headerPassphrase string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		if headerPassphrase != "" {
+			req.Header.Set(libhttp.DangerPassphraseHeaderName, headerPassphrase)
+		}
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+	serve := func(target string) *httptest.ResponseRecorder {
+		return serveWithHeader(target, "")
+	}
+	Context("without passphrase", func() {
+		var resp *httptest.ResponseRecorder
+		JustBeforeEach(func() {
+			resp = serve("http://example.com/delete")
+		})
+		It("returns 403", func() {
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+		})
+		It("mentions header and query parameter", func() {
+			Expect(resp.Body.String()).To(ContainSubstring(libhttp.DangerPassphraseHeaderName))
+			Expect(resp.Body.String()).To(ContainSubstring("?passphrase="))
+		})
+		It("mentions default expiry", func() {
+			Expect(resp.Body.String()).To(ContainSubstring("within 5 minutes"))
+		})
+		It("does not call handler", func() {
+			Expect(counter).To(Equal(0))
+		})
+		It("reports passphrase to sink", func() {
+			Expect(passphrasePath).To(Equal("/delete"))
+			Expect(passphraseExpiresAt).To(Equal(time.Date(2026, 10, 16, 12, 5, 0, 0, time.UTC)))
+		})
+		It("generates passphrase with default length", func() {
+			Expect(passphrase).To(HaveLen(16))
+		})
+		Context("with custom options", func() {
+			BeforeEach(func() {
+				options = libhttp.DangerousHandlerWrapperOptions{
+					Expiry:          2 * time.Minute,
+					PassphraseBytes: 24,
+					PassphraseSink:  sink,
+				}
+			})
+			It("mentions custom expiry", func() {
+				Expect(resp.Body.String()).To(ContainSubstring("within 2 minutes"))
+			})
+			It("generates longer passphrase", func() {
+				Expect(passphrase).To(HaveLen(32))
+			})
+		})
+	})
+	Context("with passphrase", func() {
+		JustBeforeEach(func() {
+			serve("http://example.com/delete")
+			Expect(passphrase).NotTo(BeEmpty())
+		})
+		It("calls handler with valid passphrase", func() {
+			resp := serve("http://example.com/delete?passphrase=" + passphrase)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(Equal("done"))
+			Expect(counter).To(Equal(1))
+		})
+		It("calls handler with valid header passphrase", func() {
+			resp := serveWithHeader("http://example.com/delete", passphrase)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(counter).To(Equal(1))
+		})
+		It("prefers header over query parameter", func() {
+			resp := serveWithHeader("http://example.com/delete?passphrase=banana", passphrase)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(counter).To(Equal(1))
+		})
+		It("rejects invalid header even with valid query parameter", func() {
+			resp := serveWithHeader("http://example.com/delete?passphrase="+passphrase, "banana")
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+		It("rejects invalid passphrase", func() {
+			resp := serve("http://example.com/delete?passphrase=banana")
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+		It("rejects passphrase with valid prefix", func() {
+			resp := serve("http://example.com/delete?passphrase=" + passphrase[:len(passphrase)-1])
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+		It("rejects passphrase with additional suffix", func() {
+			resp := serve("http://example.com/delete?passphrase=" + passphrase + "x")
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+		It("rejects expired passphrase", func() {
+			currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 12, 5, 0, 0, time.UTC)))
+			resp := serve("http://example.com/delete?passphrase=" + passphrase)
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+	})
+	Context("with confirmation", func() {
+		BeforeEach(func() {
+			libhttp.WithDangerousConfirmation("production-db")(&options)
+		})
+		JustBeforeEach(func() {
+			serve("http://example.com/delete")
+			Expect(passphrase).NotTo(BeEmpty())
+		})
+		It("rejects valid passphrase without confirmation", func() {
+			resp := serve("http://example.com/delete?passphrase=" + passphrase)
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(resp.Body.String()).To(ContainSubstring("confirmation missing or mismatched"))
+			Expect(counter).To(Equal(0))
+		})
+		It("rejects valid passphrase with wrong confirmation", func() {
+			resp := serve("http://example.com/delete?confirm=staging-db&passphrase=" + passphrase)
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+		It("calls handler with passphrase and confirmation", func() {
+			resp := serve("http://example.com/delete?confirm=production-db&passphrase=" + passphrase)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(counter).To(Equal(1))
+		})
+		It("calls handler with confirmation header", func() {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/delete?passphrase="+passphrase, nil)
+			req.Header.Set(libhttp.DangerConfirmationHeaderName, "production-db")
+			handler.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(counter).To(Equal(1))
+		})
+		It("ignores confirmation in form body", func() {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/delete?passphrase="+passphrase, strings.NewReader("confirm=production-db"))
+			req.Header.Set(libhttp.ContentTypeHeaderName, "application/x-www-form-urlencoded")
+			handler.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusForbidden))
+			Expect(counter).To(Equal(0))
+		})
+	})
+	Context("with functional options", func() {
+		JustBeforeEach(func() {
+			handler = libhttp.NewDangerousHandlerWrapperWithCurrentDateTime(
+				http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+					counter++
+				}),
+				currentDateTime,
+				libhttp.WithDangerousExpiry(2*time.Minute),
+				libhttp.WithDangerousPassphraseBytes(24),
+				libhttp.WithDangerousPassphraseSink(sink),
+			)
+		})
+		It("applies expiry and passphrase length", func() {
+			resp := serve("http://example.com/delete")
+			Expect(resp.Body.String()).To(ContainSubstring("within 2 minutes"))
+			Expect(passphrase).To(HaveLen(32))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/bborbe/errors"
+)
+
+// DefaultDecodeJSONRequestMaxBodyBytes is the size of the largest request body DecodeJSONRequest accepts by default.
+const DefaultDecodeJSONRequestMaxBodyBytes = 1 << 20
+
+// DecodeJSONRequestOptions configures DecodeJSONRequest.
+type DecodeJSONRequestOptions struct {
+	// MaxBodyBytes limits the size of the request body
+	MaxBodyBytes int64
+	// DisallowUnknownFields rejects fields not present in the target type
+	DisallowUnknownFields bool
+}
+
+// WithDecodeJSONRequestMaxBodyBytes limits the size of the request body, larger bodies are rejected with 413.
+func WithDecodeJSONRequestMaxBodyBytes(maxBodyBytes int64) func(*DecodeJSONRequestOptions) {
+	return func(options *DecodeJSONRequestOptions) {
+		options.MaxBodyBytes = maxBodyBytes
+	}
+}
+
+// WithDecodeJSONRequestDisallowUnknownFields rejects fields not present in the target type.
+func WithDecodeJSONRequestDisallowUnknownFields() func(*DecodeJSONRequestOptions) {
+	return func(options *DecodeJSONRequestOptions) {
+		options.DisallowUnknownFields = true
+	}
+}
+
+// DecodeJSONRequest decodes the JSON body of the request into T. Data after the JSON value is rejected.
+// All failures are returned as errors with ErrorCodeValidation, so they flow through NewJSONErrorHandler.
+func DecodeJSONRequest[T any](ctx context.Context, req *http.Request, optionFns ...func(*DecodeJSONRequestOptions)) (T, error) {
+	var result T
+	options := DecodeJSONRequestOptions{
+		MaxBodyBytes: DefaultDecodeJSONRequestMaxBodyBytes,
+	}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+
+	if !IsContentType(req.Header.Get(ContentTypeHeaderName), ApplicationJsonContentType) {
+		return result, WrapWithCode(
+			errors.Errorf(ctx, "content type '%s' is not %s", req.Header.Get(ContentTypeHeaderName), ApplicationJsonContentType),
+			ErrorCodeValidation,
+			http.StatusUnsupportedMediaType,
+		)
+	}
+	if req.Body == nil {
+		return result, WrapWithCode(errors.New(ctx, "request body is empty"), ErrorCodeValidation, http.StatusBadRequest)
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, req.Body, options.MaxBodyBytes))
+	if options.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&result); err != nil {
+		if stderrors.Is(err, io.EOF) {
+			return result, WrapWithCode(errors.New(ctx, "request body is empty"), ErrorCodeValidation, http.StatusBadRequest)
+		}
+		return result, decodeJSONRequestError(ctx, err, options.MaxBodyBytes)
+	}
+	var trailing json.RawMessage
+	if err := decoder.Decode(&trailing); !stderrors.Is(err, io.EOF) {
+		if err != nil {
+			return result, decodeJSONRequestError(ctx, err, options.MaxBodyBytes)
+		}
+		return result, WrapWithCode(errors.New(ctx, "request body contains data after the json value"), ErrorCodeValidation, http.StatusBadRequest)
+	}
+	return result, nil
+}
+
+// decodeJSONRequestError returns 413 if the body exceeds maxBodyBytes, otherwise a validation error.
+func decodeJSONRequestError(ctx context.Context, err error, maxBodyBytes int64) error {
+	var maxBytesError *http.MaxBytesError
+	if stderrors.As(err, &maxBytesError) {
+		return WrapWithCode(
+			errors.Errorf(ctx, "request body exceeds %d bytes", maxBodyBytes),
+			ErrorCodePayloadTooLarge,
+			http.StatusRequestEntityTooLarge,
+		)
+	}
+	return WrapWithCode(errors.Wrapf(ctx, err, "decode json failed"), ErrorCodeValidation, http.StatusBadRequest)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type decodeTestPerson struct {
+	Name string `json:"name"`
+}
+
+var _ = Describe("DecodeJSONRequest", func() {
+	var ctx context.Context
+	var err error
+	var req *http.Request
+	var body string
+	var contentType string
+	var optionFns []func(*libhttp.DecodeJSONRequestOptions)
+	var result decodeTestPerson
+	BeforeEach(func() {
+		ctx = context.Background()
+		body = `{"name":"Ben"}`
+		contentType = "application/json; charset=utf-8"
+		optionFns = nil
+	})
+	JustBeforeEach(func() {
+		req = httptest.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(body))
+		req.Header.Set(libhttp.ContentTypeHeaderName, contentType)
+		result, err = libhttp.DecodeJSONRequest[decodeTestPerson](ctx, req, optionFns...)
+	})
+	expectValidationError := func(expectedStatusCode int) {
+		ExpectWithOffset(1, err).NotTo(BeNil())
+		code, statusCode, _ := libhttp.ResolveError(err)
+		ExpectWithOffset(1, code).To(Equal(libhttp.ErrorCodeValidation))
+		ExpectWithOffset(1, statusCode).To(Equal(expectedStatusCode))
+	}
+	Context("valid json", func() {
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("returns decoded value", func() {
+			Expect(result).To(Equal(decodeTestPerson{Name: "Ben"}))
+		})
+	})
+	Context("wrong content type", func() {
+		BeforeEach(func() {
+			contentType = "text/plain"
+		})
+		It("returns validation error", func() {
+			expectValidationError(http.StatusUnsupportedMediaType)
+		})
+	})
+	Context("unknown fields", func() {
+		BeforeEach(func() {
+			body = `{"name":"Ben","age":42}`
+		})
+		It("are accepted by default", func() {
+			Expect(err).To(BeNil())
+		})
+		Context("disallowed", func() {
+			BeforeEach(func() {
+				optionFns = append(optionFns, libhttp.WithDecodeJSONRequestDisallowUnknownFields())
+			})
+			It("returns validation error", func() {
+				expectValidationError(http.StatusBadRequest)
+			})
+		})
+	})
+	Context("empty body", func() {
+		BeforeEach(func() {
+			body = ""
+		})
+		It("returns validation error", func() {
+			expectValidationError(http.StatusBadRequest)
+		})
+	})
+	Context("invalid json", func() {
+		BeforeEach(func() {
+			body = `{"name":`
+		})
+		It("returns validation error", func() {
+			expectValidationError(http.StatusBadRequest)
+		})
+	})
+	Context("trailing data", func() {
+		BeforeEach(func() {
+			body = `{"name":"Ben"}{"name":"Alice"}`
+		})
+		It("returns validation error", func() {
+			expectValidationError(http.StatusBadRequest)
+		})
+	})
+	Context("trailing whitespace", func() {
+		BeforeEach(func() {
+			body = "{\"name\":\"Ben\"}\n"
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("body too large", func() {
+		BeforeEach(func() {
+			body = `{"name":"` + strings.Repeat("a", 100) + `"}`
+			optionFns = append(optionFns, libhttp.WithDecodeJSONRequestMaxBodyBytes(10))
+		})
+		It("returns payload too large error", func() {
+			var errorWithCode libhttp.ErrorWithCode
+			Expect(errors.As(err, &errorWithCode)).To(BeTrue())
+			Expect(errorWithCode.Code()).To(Equal(libhttp.ErrorCodePayloadTooLarge))
+		})
+	})
+})
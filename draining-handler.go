@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+)
+
+// DrainingOptions configures NewDrainingHandler.
+type DrainingOptions struct {
+	// ReadinessPaths return 503 after drain
+	ReadinessPaths []string
+	// RejectPathPrefixes reject new requests with 503 after drain
+	RejectPathPrefixes []string
+}
+
+// WithDrainingReadinessPaths replaces the readiness paths, default is /readyz.
+func WithDrainingReadinessPaths(paths ...string) func(*DrainingOptions) {
+	return func(options *DrainingOptions) {
+		options.ReadinessPaths = paths
+	}
+}
+
+// WithDrainingRejectPathPrefixes rejects new requests to paths with one of the prefixes after drain.
+func WithDrainingRejectPathPrefixes(prefixes ...string) func(*DrainingOptions) {
+	return func(options *DrainingOptions) {
+		options.RejectPathPrefixes = append(options.RejectPathPrefixes, prefixes...)
+	}
+}
+
+// NewDrainingHandler returns a handler and a drain func.
+// After drain is called the readiness paths return 503, so load balancers stop routing new requests,
+// while all other requests, except the configured reject paths, are still passed to next.
+// Call drain as soon as shutdown begins, some time before the server is shut down.
+func NewDrainingHandler(next http.Handler, optionFns ...func(*DrainingOptions)) (http.Handler, func()) {
+	options := DrainingOptions{
+		ReadinessPaths: []string{"/readyz"},
+	}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	var draining atomic.Bool
+	handler := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if draining.Load() && options.reject(req.URL.Path) {
+			glog.V(2).Infof("reject %s request to %s while draining", req.Method, req.URL.Path)
+			sendHealthResponse(req.Context(), resp, HealthResponse{Status: HealthStatusUnavailable, Errors: []string{"draining"}}, http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+	drain := func() {
+		if draining.CompareAndSwap(false, true) {
+			glog.V(0).Infof("draining started")
+		}
+	}
+	return handler, drain
+}
+
+func (d DrainingOptions) reject(path string) bool {
+	for _, readinessPath := range d.ReadinessPaths {
+		if path == readinessPath {
+			return true
+		}
+	}
+	for _, prefix := range d.RejectPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
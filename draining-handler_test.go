@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DrainingHandler", func() {
+	var handler http.Handler
+	var drain func()
+	BeforeEach(func() {
+		handler, drain = libhttp.NewDrainingHandler(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				resp.WriteHeader(http.StatusOK)
+			}),
+			libhttp.WithDrainingRejectPathPrefixes("/api/"),
+		)
+	})
+	serve := func(path string) int {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		return resp.Code
+	}
+	It("passes requests before drain", func() {
+		Expect(serve("/readyz")).To(Equal(http.StatusOK))
+		Expect(serve("/api/orders")).To(Equal(http.StatusOK))
+		Expect(serve("/metrics")).To(Equal(http.StatusOK))
+	})
+	It("returns 503 for readiness and reject paths after drain", func() {
+		drain()
+		drain()
+		Expect(serve("/readyz")).To(Equal(http.StatusServiceUnavailable))
+		Expect(serve("/api/orders")).To(Equal(http.StatusServiceUnavailable))
+		Expect(serve("/metrics")).To(Equal(http.StatusOK))
+	})
+	It("uses custom readiness paths", func() {
+		handler, drain = libhttp.NewDrainingHandler(http.NotFoundHandler(), libhttp.WithDrainingReadinessPaths("/ready"))
+		drain()
+		Expect(serve("/ready")).To(Equal(http.StatusServiceUnavailable))
+		Expect(serve("/readyz")).To(Equal(http.StatusNotFound))
+	})
+})
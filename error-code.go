@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrorWithStatusCode is an error that carries the HTTP status code to respond with.
+type ErrorWithStatusCode interface {
+	error
+	StatusCode() int
+}
+
+// ErrorWithCode is an error that carries an error code like ErrorCodeValidation.
+type ErrorWithCode interface {
+	error
+	Code() string
+}
+
+// ErrorWithDetails is an error that carries structured details for the ErrorResponse.
+type ErrorWithDetails interface {
+	error
+	Details() map[string]any
+}
+
+// WrapWithStatusCode attaches the HTTP status code to the given error.
+func WrapWithStatusCode(err error, statusCode int) error {
+	return &codeError{
+		err:        err,
+		statusCode: statusCode,
+	}
+}
+
+// WrapWithCode attaches the error code and HTTP status code to the given error.
+func WrapWithCode(err error, code string, statusCode int) error {
+	return &codeError{
+		err:        err,
+		code:       code,
+		statusCode: statusCode,
+	}
+}
+
+// WrapWithDetails attaches the error code, HTTP status code and details for the ErrorResponse to the given error.
+func WrapWithDetails(err error, code string, statusCode int, details map[string]any) error {
+	return &detailsError{
+		codeError: codeError{
+			err:        err,
+			code:       code,
+			statusCode: statusCode,
+		},
+		details: details,
+	}
+}
+
+// WrapWithCodef creates an error like errors.Errorf and attaches the error code and HTTP status code.
+func WrapWithCodef(ctx context.Context, code string, statusCode int, format string, args ...any) error {
+	return WrapWithCode(errors.Errorf(ctx, format, args...), code, statusCode)
+}
+
+// WrapWithDetailsf creates an error like errors.Errorf and attaches the error code, HTTP status code and details.
+func WrapWithDetailsf(ctx context.Context, code string, statusCode int, details map[string]any, format string, args ...any) error {
+	return WrapWithDetails(errors.Errorf(ctx, format, args...), code, statusCode, details)
+}
+
+// WrapWithConflict wraps the error with ErrorCodeConflict and status 409.
+func WrapWithConflict(err error) error {
+	return WrapWithCode(err, ErrorCodeConflict, http.StatusConflict)
+}
+
+// WrapWithRateLimited wraps the error with ErrorCodeRateLimited and status 429.
+func WrapWithRateLimited(err error) error {
+	return WrapWithCode(err, ErrorCodeRateLimited, http.StatusTooManyRequests)
+}
+
+// WrapWithTimeout wraps the error with ErrorCodeTimeout and status 504.
+func WrapWithTimeout(err error) error {
+	return WrapWithCode(err, ErrorCodeTimeout, http.StatusGatewayTimeout)
+}
+
+// WrapWithBadGateway wraps the error with ErrorCodeBadGateway and status 502.
+func WrapWithBadGateway(err error) error {
+	return WrapWithCode(err, ErrorCodeBadGateway, http.StatusBadGateway)
+}
+
+type codeError struct {
+	err        error
+	code       string
+	statusCode int
+}
+
+func (c *codeError) Error() string {
+	return c.err.Error()
+}
+
+func (c *codeError) Unwrap() error {
+	return c.err
+}
+
+func (c *codeError) Cause() error {
+	return c.err
+}
+
+func (c *codeError) Code() string {
+	return c.code
+}
+
+func (c *codeError) StatusCode() int {
+	return c.statusCode
+}
+
+type detailsError struct {
+	codeError
+	details map[string]any
+}
+
+func (d *detailsError) Details() map[string]any {
+	return d.details
+}
+
+// ResolveError returns the error code, HTTP status code and details of the given error.
+// Errors without code or status code default to ErrorCodeInternal and 500.
+func ResolveError(err error) (string, int, map[string]any) {
+	code := ErrorCodeInternal
+	statusCode := http.StatusInternalServerError
+
+	var errorWithStatusCode ErrorWithStatusCode
+	if errors.As(err, &errorWithStatusCode) && errorWithStatusCode.StatusCode() != 0 {
+		statusCode = errorWithStatusCode.StatusCode()
+	}
+	var errorWithCode ErrorWithCode
+	if errors.As(err, &errorWithCode) && errorWithCode.Code() != "" {
+		code = errorWithCode.Code()
+	}
+
+	var details map[string]any
+	for key, value := range errors.DataFromError(err) {
+		if details == nil {
+			details = make(map[string]any)
+		}
+		details[key] = value
+	}
+	var errorWithDetails ErrorWithDetails
+	if errors.As(err, &errorWithDetails) {
+		for key, value := range errorWithDetails.Details() {
+			if details == nil {
+				details = make(map[string]any)
+			}
+			details[key] = value
+		}
+	}
+	return code, statusCode, details
+}
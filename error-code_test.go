@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorCode", func() {
+	var ctx context.Context
+	var baseErr error
+	BeforeEach(func() {
+		ctx = context.Background()
+		baseErr = errors.New(ctx, "banana")
+	})
+	DescribeTable("wrap helper",
+		func(wrap func(err error) error, expectedCode string, expectedStatusCode int) {
+			err := errors.Wrapf(ctx, wrap(baseErr), "wrapped")
+
+			var errorWithCode libhttp.ErrorWithCode
+			Expect(errors.As(err, &errorWithCode)).To(BeTrue())
+			Expect(errorWithCode.Code()).To(Equal(expectedCode))
+
+			var errorWithStatusCode libhttp.ErrorWithStatusCode
+			Expect(errors.As(err, &errorWithStatusCode)).To(BeTrue())
+			Expect(errorWithStatusCode.StatusCode()).To(Equal(expectedStatusCode))
+
+			Expect(errors.Is(err, baseErr)).To(BeTrue())
+		},
+		Entry("conflict", libhttp.WrapWithConflict, libhttp.ErrorCodeConflict, http.StatusConflict),
+		Entry("rate limited", libhttp.WrapWithRateLimited, libhttp.ErrorCodeRateLimited, http.StatusTooManyRequests),
+		Entry("timeout", libhttp.WrapWithTimeout, libhttp.ErrorCodeTimeout, http.StatusGatewayTimeout),
+		Entry("bad gateway", libhttp.WrapWithBadGateway, libhttp.ErrorCodeBadGateway, http.StatusBadGateway),
+	)
+	It("wraps with status code only", func() {
+		err := libhttp.WrapWithStatusCode(baseErr, http.StatusTeapot)
+		var errorWithStatusCode libhttp.ErrorWithStatusCode
+		Expect(errors.As(err, &errorWithStatusCode)).To(BeTrue())
+		Expect(errorWithStatusCode.StatusCode()).To(Equal(http.StatusTeapot))
+		Expect(err.Error()).To(Equal("banana"))
+	})
+	It("creates a formatted error with code", func() {
+		err := libhttp.WrapWithCodef(ctx, libhttp.ErrorCodeNotFound, http.StatusNotFound, "user %d not found", 42)
+		Expect(err.Error()).To(Equal("user 42 not found"))
+		code, statusCode, details := libhttp.ResolveError(err)
+		Expect(code).To(Equal(libhttp.ErrorCodeNotFound))
+		Expect(statusCode).To(Equal(http.StatusNotFound))
+		Expect(details).To(BeNil())
+	})
+	It("creates a formatted error with details", func() {
+		err := libhttp.WrapWithDetailsf(ctx, libhttp.ErrorCodeConflict, http.StatusConflict, map[string]any{"id": 42}, "user %s exists", "alice")
+		Expect(err.Error()).To(Equal("user alice exists"))
+		code, statusCode, details := libhttp.ResolveError(err)
+		Expect(code).To(Equal(libhttp.ErrorCodeConflict))
+		Expect(statusCode).To(Equal(http.StatusConflict))
+		Expect(details).To(Equal(map[string]any{"id": 42}))
+	})
+	Context("ResolveError", func() {
+		var err error
+		var code string
+		var statusCode int
+		var details map[string]any
+		JustBeforeEach(func() {
+			code, statusCode, details = libhttp.ResolveError(err)
+		})
+		Context("plain error", func() {
+			BeforeEach(func() {
+				err = baseErr
+			})
+			It("returns defaults", func() {
+				Expect(code).To(Equal(libhttp.ErrorCodeInternal))
+				Expect(statusCode).To(Equal(http.StatusInternalServerError))
+				Expect(details).To(BeNil())
+			})
+		})
+		Context("coded error", func() {
+			BeforeEach(func() {
+				err = errors.Wrapf(ctx, libhttp.WrapWithCode(baseErr, libhttp.ErrorCodeValidation, http.StatusBadRequest), "wrapped")
+			})
+			It("returns code and status code", func() {
+				Expect(code).To(Equal(libhttp.ErrorCodeValidation))
+				Expect(statusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("status only error", func() {
+			BeforeEach(func() {
+				err = libhttp.WrapWithStatusCode(baseErr, http.StatusNotFound)
+			})
+			It("returns default code and status code", func() {
+				Expect(code).To(Equal(libhttp.ErrorCodeInternal))
+				Expect(statusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("error with details", func() {
+			BeforeEach(func() {
+				err = errors.AddDataToError(
+					libhttp.WrapWithCode(baseErr, libhttp.ErrorCodeNotFound, http.StatusNotFound),
+					map[string]string{"id": "123"},
+				)
+			})
+			It("returns details", func() {
+				Expect(code).To(Equal(libhttp.ErrorCodeNotFound))
+				Expect(details).To(Equal(map[string]any{"id": "123"}))
+			})
+		})
+	})
+})
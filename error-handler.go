@@ -23,15 +23,65 @@ func (w WithErrorFunc) ServeHTTP(ctx context.Context, resp http.ResponseWriter,
 	return w(ctx, resp, req)
 }
 
-func NewErrorHandler(handlerWithError WithError) http.Handler {
+// InternalErrorMessage is sent instead of the error message of 5xx errors if HideInternalMessages is set.
+const InternalErrorMessage = "internal server error"
+
+// ErrorHandlerOptions configures how errors are sent to the client.
+type ErrorHandlerOptions struct {
+	// HideInternalMessages sends InternalErrorMessage for 5xx errors instead of the error message,
+	// which might contain internal details like SQL errors or file paths. The full error is still logged.
+	HideInternalMessages bool
+}
+
+// WithHideInternalMessages sends InternalErrorMessage to the client for 5xx errors.
+func WithHideInternalMessages() func(*ErrorHandlerOptions) {
+	return func(options *ErrorHandlerOptions) {
+		options.HideInternalMessages = true
+	}
+}
+
+func buildErrorHandlerOptions(optionFns ...func(*ErrorHandlerOptions)) ErrorHandlerOptions {
+	var options ErrorHandlerOptions
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return options
+}
+
+// NewErrorHandler writes errors as plain text with the status code resolved by ResolveError.
+func NewErrorHandler(handlerWithError WithError, optionFns ...func(*ErrorHandlerOptions)) http.Handler {
+	options := buildErrorHandlerOptions(optionFns...)
+	return newErrorHandler(handlerWithError, func(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error) {
+		sendPlainTextError(ctx, resp, req, err, options)
+	})
+}
+
+type sendErrorFunc func(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error)
+
+func newErrorHandler(handlerWithError WithError, sendError sendErrorFunc) http.Handler {
 	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		glog.V(3).Infof("handle %s request to %s started", req.Method, req.URL.Path)
 		if err := handlerWithError.ServeHTTP(ctx, resp, req); err != nil {
-			http.Error(resp, fmt.Sprintf("request failed: %v", err), http.StatusInternalServerError)
-			glog.V(1).Infof("handle %s request to %s failed: %v", req.Method, req.URL.Path, err)
+			// sendError logs the error with the request logger
+			sendError(ctx, resp, req, err)
 			return
 		}
 		glog.V(3).Infof("handle %s request to %s completed", req.Method, req.URL.Path)
 	})
 }
+
+func sendPlainTextError(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error, options ErrorHandlerOptions) {
+	code, statusCode, _ := ResolveError(err)
+	logRequestError(ctx, statusCode, code, err)
+	if hideErrorMessage(statusCode, options) {
+		http.Error(resp, InternalErrorMessage, statusCode)
+		return
+	}
+	http.Error(resp, fmt.Sprintf("request failed: %v", err), statusCode)
+}
+
+// hideErrorMessage returns true if the error message must not be sent to the client.
+func hideErrorMessage(statusCode int, options ErrorHandlerOptions) bool {
+	return options.HideInternalMessages && statusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorHandler", func() {
+	var logBuf *bufferRequestLogger
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	var handlerErr error
+	var optionFns []func(*libhttp.ErrorHandlerOptions)
+	BeforeEach(func() {
+		logBuf = &bufferRequestLogger{}
+		optionFns = nil
+		req = httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		req = req.WithContext(libhttp.ContextWithLogger(req.Context(), logBuf))
+	})
+	JustBeforeEach(func() {
+		resp = httptest.NewRecorder()
+		libhttp.NewErrorHandler(libhttp.WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+			return handlerErr
+		}), optionFns...).ServeHTTP(resp, req)
+	})
+	Context("internal error", func() {
+		BeforeEach(func() {
+			handlerErr = errors.New(context.Background(), "select failed: table users missing")
+		})
+		It("sends the error message by default", func() {
+			Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+			Expect(resp.Body.String()).To(ContainSubstring("request failed: select failed: table users missing"))
+		})
+		Context("with hidden internal messages", func() {
+			BeforeEach(func() {
+				optionFns = append(optionFns, libhttp.WithHideInternalMessages())
+			})
+			It("sends a generic message", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal(libhttp.InternalErrorMessage + "\n"))
+			})
+			It("logs the full error", func() {
+				Expect(logBuf.String()).To(ContainSubstring("select failed: table users missing"))
+			})
+		})
+	})
+	Context("client error with hidden internal messages", func() {
+		BeforeEach(func() {
+			handlerErr = libhttp.WrapWithStatusCode(errors.New(context.Background(), "name is required"), http.StatusBadRequest)
+			optionFns = append(optionFns, libhttp.WithHideInternalMessages())
+		})
+		It("sends the error message", func() {
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body.String()).To(ContainSubstring("request failed: name is required"))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+// ErrorResponseBuilder creates an ErrorResponse step by step.
+// The details are written with sorted keys, so equal responses serialize byte-identical.
+type ErrorResponseBuilder interface {
+	WithCode(code string) ErrorResponseBuilder
+	WithMessage(message string) ErrorResponseBuilder
+	WithDetail(key string, value any) ErrorResponseBuilder
+	WithDetails(details map[string]any) ErrorResponseBuilder
+	Build() ErrorResponse
+	Send(ctx context.Context, resp http.ResponseWriter, statusCode int) error
+}
+
+// NewErrorResponseBuilder returns a builder for an ErrorResponse with the given code and message.
+func NewErrorResponseBuilder(code string, message string) ErrorResponseBuilder {
+	return &errorResponseBuilder{
+		code:    code,
+		message: message,
+	}
+}
+
+type errorResponseBuilder struct {
+	code    string
+	message string
+	details map[string]any
+}
+
+func (e *errorResponseBuilder) WithCode(code string) ErrorResponseBuilder {
+	e.code = code
+	return e
+}
+
+func (e *errorResponseBuilder) WithMessage(message string) ErrorResponseBuilder {
+	e.message = message
+	return e
+}
+
+// WithDetail adds a single detail, an existing detail with the same key is replaced.
+func (e *errorResponseBuilder) WithDetail(key string, value any) ErrorResponseBuilder {
+	if e.details == nil {
+		e.details = make(map[string]any)
+	}
+	e.details[key] = value
+	return e
+}
+
+// WithDetails adds all given details, existing details with the same key are replaced.
+func (e *errorResponseBuilder) WithDetails(details map[string]any) ErrorResponseBuilder {
+	for key, value := range details {
+		e.WithDetail(key, value)
+	}
+	return e
+}
+
+// Build returns the ErrorResponse. Later changes of the builder do not modify it.
+func (e *errorResponseBuilder) Build() ErrorResponse {
+	var details map[string]any
+	if len(e.details) > 0 {
+		details = make(map[string]any, len(e.details))
+		for key, value := range e.details {
+			details[key] = value
+		}
+	}
+	return ErrorResponse{
+		Error: ErrorDetails{
+			Code:    e.code,
+			Message: e.message,
+			Details: details,
+		},
+	}
+}
+
+// Send writes the built ErrorResponse as JSON with the given status code.
+func (e *errorResponseBuilder) Send(ctx context.Context, resp http.ResponseWriter, statusCode int) error {
+	return SendJSONResponse(ctx, resp, e.Build(), statusCode)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorResponseBuilder", func() {
+	var builder libhttp.ErrorResponseBuilder
+	BeforeEach(func() {
+		builder = libhttp.NewErrorResponseBuilder(libhttp.ErrorCodeValidation, "validation failed")
+	})
+	It("builds response without details", func() {
+		Expect(builder.Build()).To(Equal(libhttp.ErrorResponse{
+			Error: libhttp.ErrorDetails{
+				Code:    libhttp.ErrorCodeValidation,
+				Message: "validation failed",
+			},
+		}))
+	})
+	It("builds response with details", func() {
+		errorResponse := builder.
+			WithDetail("zeta", "last").
+			WithDetails(map[string]any{"alpha": "first", "mike": 1}).
+			Build()
+		Expect(errorResponse.Error.Details).To(Equal(map[string]any{"zeta": "last", "alpha": "first", "mike": 1}))
+	})
+	It("overrides code and message", func() {
+		errorResponse := builder.WithCode(libhttp.ErrorCodeConflict).WithMessage("conflict").Build()
+		Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeConflict))
+		Expect(errorResponse.Error.Message).To(Equal("conflict"))
+	})
+	It("does not modify built responses", func() {
+		errorResponse := builder.WithDetail("alpha", "first").Build()
+		builder.WithDetail("alpha", "changed")
+		Expect(errorResponse.Error.Details["alpha"]).To(Equal("first"))
+	})
+	It("marshals details byte-identical", func() {
+		build := func() []byte {
+			content, err := json.Marshal(libhttp.NewErrorResponseBuilder(libhttp.ErrorCodeValidation, "validation failed").
+				WithDetail("zeta", "last").
+				WithDetail("alpha", "first").
+				WithDetail("mike", "middle").
+				Build())
+			Expect(err).To(BeNil())
+			return content
+		}
+		first := build()
+		for i := 0; i < 20; i++ {
+			Expect(build()).To(Equal(first))
+		}
+		Expect(string(first)).To(Equal(`{"error":{"code":"VALIDATION_ERROR","message":"validation failed","details":{"alpha":"first","mike":"middle","zeta":"last"}}}`))
+	})
+	It("sends response with status code", func() {
+		resp := httptest.NewRecorder()
+		Expect(builder.WithDetail("field", "name").Send(context.Background(), resp, http.StatusBadRequest)).To(Succeed())
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		var errorResponse libhttp.ErrorResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+		Expect(errorResponse.Error.Details).To(Equal(map[string]any{"field": "name"}))
+	})
+})
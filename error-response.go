@@ -0,0 +1,32 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+const (
+	ErrorCodeValidation      = "VALIDATION_ERROR"
+	ErrorCodeNotFound        = "NOT_FOUND"
+	ErrorCodeUnauthorized    = "UNAUTHORIZED"
+	ErrorCodeForbidden       = "FORBIDDEN"
+	ErrorCodeInternal        = "INTERNAL_ERROR"
+	ErrorCodeTimeout         = "TIMEOUT"
+	ErrorCodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+	ErrorCodeConflict        = "CONFLICT"
+	ErrorCodeRateLimited     = "RATE_LIMITED"
+	ErrorCodeBadGateway      = "BAD_GATEWAY"
+	ErrorCodeURITooLong      = "URI_TOO_LONG"
+)
+
+// ErrorResponse is the JSON body written for failed requests.
+type ErrorResponse struct {
+	Error ErrorDetails `json:"error"`
+}
+
+// ErrorDetails describes the error of an ErrorResponse.
+type ErrorDetails struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Details are additional values of the error, encoding/json writes map keys in sorted order
+	Details map[string]any `json:"details,omitempty"`
+}
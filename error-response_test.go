@@ -0,0 +1,40 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"encoding/json"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorResponse", func() {
+	newErrorResponse := func() libhttp.ErrorResponse {
+		return libhttp.ErrorResponse{
+			Error: libhttp.ErrorDetails{
+				Code:    libhttp.ErrorCodeValidation,
+				Message: "validation failed",
+				Details: map[string]any{
+					"zeta":  "last",
+					"alpha": "first",
+					"mike":  map[string]any{"b": 2, "a": 1},
+					"bravo": []string{"x", "y"},
+				},
+			},
+		}
+	}
+	It("marshals details with sorted keys", func() {
+		first, err := json.Marshal(newErrorResponse())
+		Expect(err).To(BeNil())
+		for i := 0; i < 20; i++ {
+			next, err := json.Marshal(newErrorResponse())
+			Expect(err).To(BeNil())
+			Expect(next).To(Equal(first))
+		}
+		Expect(string(first)).To(Equal(`{"error":{"code":"VALIDATION_ERROR","message":"validation failed","details":{"alpha":"first","bravo":["x","y"],"mike":{"a":1,"b":2},"zeta":"last"}}}`))
+	})
+})
@@ -8,6 +8,9 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"reflect"
+	"slices"
+	"sync"
 	"syscall"
 
 	liberrors "github.com/bborbe/errors"
@@ -17,6 +20,52 @@ type HasTimeoutError interface {
 	Timeout() bool
 }
 
+var (
+	retryableErrorsMux sync.Mutex
+	retryableErrors    []error
+)
+
+// RegisterRetryableError adds the given error to the errors IsRetryError reports as retryable.
+// Errors are matched with errors.Is, so wrapped errors are found as well.
+func RegisterRetryableError(err error) {
+	retryableErrorsMux.Lock()
+	defer retryableErrorsMux.Unlock()
+	retryableErrors = append(retryableErrors, err)
+}
+
+// UnregisterRetryableError removes the given error added with RegisterRetryableError.
+// Errors of a type that is not comparable, e.g. a struct with a slice field, can not be removed.
+func UnregisterRetryableError(err error) {
+	retryableErrorsMux.Lock()
+	defer retryableErrorsMux.Unlock()
+	retryableErrors = slices.DeleteFunc(retryableErrors, func(retryableError error) bool {
+		return sameError(retryableError, err)
+	})
+}
+
+// sameError compares the errors with == and returns false instead of panicking if their type is not comparable.
+func sameError(a error, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	errorType := reflect.TypeOf(a)
+	if errorType != reflect.TypeOf(b) || !errorType.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func isRegisteredRetryableError(err error) bool {
+	retryableErrorsMux.Lock()
+	defer retryableErrorsMux.Unlock()
+	for _, retryableError := range retryableErrors {
+		if errors.Is(err, retryableError) {
+			return true
+		}
+	}
+	return false
+}
+
 func IsRetryError(err error) bool {
 	if errors.Is(err, syscall.ECONNREFUSED) {
 		return true
@@ -27,6 +76,9 @@ func IsRetryError(err error) bool {
 	if errors.Is(err, http.ErrHandlerTimeout) {
 		return true
 	}
+	if isRegisteredRetryableError(err) {
+		return true
+	}
 	if timeoutError, ok := liberrors.Unwrap(err).(HasTimeoutError); ok {
 		return timeoutError.Timeout()
 	}
@@ -6,6 +6,7 @@ package http_test
 
 import (
 	"context"
+	stderrors "errors"
 	"net/http"
 	"syscall"
 
@@ -74,6 +75,47 @@ var _ = Describe("IsRetryError", func() {
 				Expect(isRetryError).To(BeTrue())
 			})
 		})
+		Context("registered retryable error", func() {
+			BeforeEach(func() {
+				libhttp.RegisterRetryableError(errRetryableTest)
+				DeferCleanup(libhttp.UnregisterRetryableError, errRetryableTest)
+				err = errors.Wrapf(ctx, errRetryableTest, "banana")
+			})
+			It("returns true", func() {
+				Expect(isRetryError).To(BeTrue())
+			})
+		})
+		Context("unrelated error after register", func() {
+			BeforeEach(func() {
+				libhttp.RegisterRetryableError(errRetryableTest)
+				DeferCleanup(libhttp.UnregisterRetryableError, errRetryableTest)
+				err = errors.Wrapf(ctx, stderrors.New("unrelated"), "banana")
+			})
+			It("returns false", func() {
+				Expect(isRetryError).To(BeFalse())
+			})
+		})
+		Context("unregistered retryable error", func() {
+			BeforeEach(func() {
+				libhttp.RegisterRetryableError(errRetryableTest)
+				libhttp.UnregisterRetryableError(errRetryableTest)
+				err = errors.Wrapf(ctx, errRetryableTest, "banana")
+			})
+			It("returns false", func() {
+				Expect(isRetryError).To(BeFalse())
+			})
+		})
+		Context("unregister not comparable error", func() {
+			BeforeEach(func() {
+				libhttp.RegisterRetryableError(errRetryableTest)
+				DeferCleanup(libhttp.UnregisterRetryableError, errRetryableTest)
+				libhttp.UnregisterRetryableError(notComparableError{codes: []int{503}})
+				err = errors.Wrapf(ctx, errRetryableTest, "banana")
+			})
+			It("keeps the registered error", func() {
+				Expect(isRetryError).To(BeTrue())
+			})
+		})
 		Context("wrapped syscall.ECONNREFUSED error", func() {
 			BeforeEach(func() {
 				err = errors.Wrapf(ctx, syscall.ECONNREFUSED, "banana")
@@ -84,3 +126,13 @@ var _ = Describe("IsRetryError", func() {
 		})
 	})
 })
+
+var errRetryableTest = stderrors.New("retryable test error")
+
+type notComparableError struct {
+	codes []int
+}
+
+func (n notComparableError) Error() string {
+	return "not comparable error"
+}
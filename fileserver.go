@@ -5,34 +5,121 @@
 package http
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/golang/glog"
 )
 
+// DefaultFileServerAssetExtensions are common extensions of asset files,
+// e.g. WithAssetExtensions(DefaultFileServerAssetExtensions...) to return 404 for them instead of index.html.
+var DefaultFileServerAssetExtensions = []string{
+	".js", ".mjs", ".css", ".map", ".json",
+	".png", ".jpg", ".jpeg", ".gif", ".svg", ".ico", ".webp",
+	".woff", ".woff2", ".ttf", ".eot",
+}
+
+// FileServerOptions configures which missing paths fall back to index.html.
+type FileServerOptions struct {
+	// AssetPrefixes are path prefixes of asset files, e.g. /static/. Missing files below return 404.
+	AssetPrefixes []string
+	// AssetExtensions are extensions of asset files, e.g. .js. Missing files with it return 404. Empty by default.
+	AssetExtensions []string
+	// CacheControls sets the Cache-Control header of the first rule matching the served file
+	CacheControls []CacheControlRule
+}
+
+// CacheControlRule sets Cache-Control to Value for files matching Pattern.
+// Pattern is matched with path.Match against the path, e.g. /static/*, and against the file name, e.g. *.js.
+type CacheControlRule struct {
+	Pattern string
+	Value   string
+}
+
+// WithAssetPrefixes adds path prefixes that return 404 instead of index.html if the file is missing.
+func WithAssetPrefixes(prefixes ...string) func(*FileServerOptions) {
+	return func(options *FileServerOptions) {
+		options.AssetPrefixes = append(options.AssetPrefixes, prefixes...)
+	}
+}
+
+// WithAssetExtensions replaces the extensions that return 404 instead of index.html if the file is missing.
+func WithAssetExtensions(extensions ...string) func(*FileServerOptions) {
+	return func(options *FileServerOptions) {
+		options.AssetExtensions = extensions
+	}
+}
+
+// WithCacheControl adds a Cache-Control rule, e.g. WithCacheControl("index.html", "no-cache").
+// Rules are checked in order of registration.
+func WithCacheControl(pattern string, value string) func(*FileServerOptions) {
+	return func(options *FileServerOptions) {
+		options.CacheControls = append(options.CacheControls, CacheControlRule{Pattern: pattern, Value: value})
+	}
+}
+
+// FileServer serves the files of root for a single page application.
+// Missing files fall back to index.html, except for asset paths which return 404.
 func FileServer(
 	root string,
 	prefix string,
+	optionFns ...func(*FileServerOptions),
+) http.Handler {
+	return newFileServer(&dirFileSource{root: root}, prefix, optionFns...)
+}
+
+// FileServerFS is like FileServer, but serves the files of fsys, e.g. an embed.FS.
+func FileServerFS(
+	fsys fs.FS,
+	prefix string,
+	optionFns ...func(*FileServerOptions),
 ) http.Handler {
+	return newFileServer(&fsFileSource{fsys: fsys}, prefix, optionFns...)
+}
+
+func newFileServer(source fileSource, prefix string, optionFns ...func(*FileServerOptions)) http.Handler {
+	options := FileServerOptions{}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
 	return &fileServer{
-		root:        root,
+		source:      source,
 		prefix:      prefix,
 		defaultFile: "index.html",
+		options:     options,
 	}
 }
 
+// fileSource checks and serves the files of a FileServer.
+type fileSource interface {
+	// Stat returns an error matching fs.ErrNotExist if the file does not exist
+	Stat(name string) (fs.FileInfo, error)
+	ServeFile(resp http.ResponseWriter, req *http.Request, name string)
+}
+
 type fileServer struct {
-	root        string
+	source      fileSource
 	prefix      string
 	defaultFile string
+	options     FileServerOptions
 }
 
 // ServeHTTP serves index.html if not found
 func (f *fileServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 
+	if containsDotDot(req.URL.Path) {
+		glog.V(1).Infof("path '%s' contains '..' => reject", req.URL.Path)
+		http.NotFound(resp, req)
+		return
+	}
+
 	/* copied from http.ServeHTTP start */
 	upath := req.URL.Path
 	if !strings.HasPrefix(upath, "/") {
@@ -49,14 +136,170 @@ func (f *fileServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	file, err := http.Dir(f.root).Open(name)
-	if err != nil && os.IsNotExist(err) {
+	info, err := f.source.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if f.isAsset(name) {
+			glog.V(3).Infof("asset '%s' not found", name)
+			http.NotFound(resp, req)
+			return
+		}
 		glog.V(3).Infof("file '%s' not found => serve %s", name, f.defaultFile)
-		http.ServeFile(resp, req, path.Join(f.root, f.defaultFile))
+		name = f.defaultFile
+		info, err = f.source.Stat(name)
+	}
+	if err != nil {
+		glog.V(2).Infof("open file '%s' failed: %v", name, err)
+		http.NotFound(resp, req)
 		return
 	}
-	defer file.Close()
-	path := path.Join(f.root, name)
-	glog.V(3).Infof("serve file '%s'", path)
-	http.ServeFile(resp, req, path)
+	glog.V(3).Infof("serve file '%s'", name)
+	f.setCacheHeaders(resp, name, info)
+	f.source.ServeFile(resp, req, name)
+}
+
+// setCacheHeaders sets the Cache-Control of the first matching rule and a weak ETag
+// of size and modification time, which is used by http.ServeFile for If-None-Match.
+func (f *fileServer) setCacheHeaders(resp http.ResponseWriter, name string, info fs.FileInfo) {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	for _, rule := range f.options.CacheControls {
+		if matchPath(rule.Pattern, name) {
+			resp.Header().Set("Cache-Control", rule.Value)
+			break
+		}
+	}
+	if !info.IsDir() {
+		resp.Header().Set("ETag", fmt.Sprintf(`W/"%x-%x"`, info.Size(), info.ModTime().UnixNano()))
+	}
+}
+
+func matchPath(pattern string, name string) bool {
+	if ok, _ := path.Match(pattern, name); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(name))
+	return ok
+}
+
+// dirFileSource serves files of a directory on disk.
+type dirFileSource struct {
+	root string
+}
+
+func (d *dirFileSource) Stat(name string) (fs.FileInfo, error) {
+	filePath, err := d.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(filePath)
+}
+
+func (d *dirFileSource) ServeFile(resp http.ResponseWriter, req *http.Request, name string) {
+	filePath, err := d.resolve(name)
+	if err != nil {
+		glog.V(1).Infof("resolve file '%s' failed => reject: %v", name, err)
+		http.NotFound(resp, req)
+		return
+	}
+	http.ServeFile(resp, req, filePath)
+}
+
+// fsFileSource serves files of a fs.FS.
+type fsFileSource struct {
+	fsys fs.FS
+}
+
+func (f *fsFileSource) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.fsys, fsName(name))
+}
+
+func (f *fsFileSource) ServeFile(resp http.ResponseWriter, req *http.Request, name string) {
+	http.ServeFileFS(resp, req, f.fsys, fsName(name))
+}
+
+// fsName converts a slash separated path into a valid fs.FS name.
+func fsName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// errOutsideOfRoot is returned by resolve if the path or the target of a symlink is outside of root.
+// It does not match fs.ErrNotExist, so the request is rejected instead of served by the index.html fallback.
+var errOutsideOfRoot = errors.New("path outside of root")
+
+// resolve returns the path of name within root.
+// It returns an error matching fs.ErrNotExist if the file does not exist
+// and errOutsideOfRoot if the path or the target of a symlink is outside of root.
+func (d *dirFileSource) resolve(name string) (string, error) {
+	root, err := filepath.Abs(d.root)
+	if err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(root, filepath.FromSlash(path.Clean("/"+name)))
+	if !isWithin(root, filePath) {
+		return "", errOutsideOfRoot
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	realPath, err := filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return "", err
+	}
+	if !isWithin(realRoot, realPath) {
+		return "", errOutsideOfRoot
+	}
+	return filePath, nil
+}
+
+// containsDotDot returns true if the raw or unescaped path has a '..' segment, separated by slash or backslash.
+func containsDotDot(rawPath string) bool {
+	unescaped, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return true
+	}
+	for _, p := range []string{rawPath, unescaped} {
+		for _, segment := range strings.FieldsFunc(p, isPathSeparator) {
+			if segment == ".." {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPathSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+func isWithin(root string, filePath string) bool {
+	rel, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// isAsset returns true if the name matches an asset prefix or extension.
+func (f *fileServer) isAsset(name string) bool {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	for _, prefix := range f.options.AssetPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	ext := strings.ToLower(path.Ext(name))
+	for _, extension := range f.options.AssetExtensions {
+		if ext == strings.ToLower(extension) {
+			return true
+		}
+	}
+	return false
 }
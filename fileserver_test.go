@@ -0,0 +1,205 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing/fstest"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileServer", func() {
+	var root string
+	var handler http.Handler
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(root, "index.html"), []byte("<html>index</html>"), 0600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(root, "static"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "static", "app.js"), []byte("console.log('app')"), 0600)).To(Succeed())
+		handler = libhttp.FileServer(
+			root,
+			"/",
+			libhttp.WithAssetPrefixes("/static/"),
+			libhttp.WithAssetExtensions(libhttp.DefaultFileServerAssetExtensions...),
+		)
+	})
+	serve := func(target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		return resp
+	}
+	It("serves existing files with content type", func() {
+		resp := serve("/static/app.js")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(ContainSubstring("javascript"))
+		Expect(resp.Body.String()).To(Equal("console.log('app')"))
+	})
+	It("falls back to index.html for client routes", func() {
+		resp := serve("/users/42")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(ContainSubstring("text/html"))
+		Expect(resp.Body.String()).To(Equal("<html>index</html>"))
+	})
+	It("returns 404 for missing js", func() {
+		resp := serve("/app.abc.js")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+	})
+	It("returns 404 for missing files below asset prefix", func() {
+		resp := serve("/static/missing")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+	})
+	It("falls back to index.html for missing js without asset extensions", func() {
+		handler = libhttp.FileServer(root, "/")
+		resp := serve("/app.abc.js")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("<html>index</html>"))
+	})
+})
+
+var _ = Describe("FileServer traversal", func() {
+	var dir string
+	var root string
+	var handler http.Handler
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		root = filepath.Join(dir, "root")
+		Expect(os.MkdirAll(root, 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "index.html"), []byte("index"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "public.txt"), []byte("public"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "outside"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "outside", "data.txt"), []byte("secret"), 0600)).To(Succeed())
+		Expect(os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(root, "link.txt"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(dir, "outside"), filepath.Join(root, "linkdir"))).To(Succeed())
+		handler = libhttp.FileServer(root, "/")
+	})
+	serve := func(target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = target
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+	DescribeTable("does not serve files outside of root",
+		func(target string) {
+			resp := serve(target)
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+			Expect(resp.Body.String()).NotTo(ContainSubstring("secret"))
+			Expect(resp.Body.String()).NotTo(ContainSubstring("index"))
+		},
+		Entry("dot dot", "/../secret.txt"),
+		Entry("encoded dot dot", "/..%2fsecret.txt"),
+		Entry("nested dot dot", "/a/../../secret.txt"),
+		Entry("backslash", "/..\\secret.txt"),
+		Entry("encoded backslash", "/..%5csecret.txt"),
+		Entry("symlink", "/link.txt"),
+		Entry("file in symlinked directory", "/linkdir/data.txt"),
+	)
+	It("does not serve absolute paths", func() {
+		resp := serve("/" + filepath.Join(dir, "secret.txt"))
+		Expect(resp.Body.String()).NotTo(ContainSubstring("secret"))
+	})
+	It("returns 404 for a symlink pointing outside of root", func() {
+		resp := serve("/link.txt")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+		Expect(resp.Body.String()).NotTo(ContainSubstring("secret"))
+	})
+	It("returns 404 for a file in a symlinked directory outside of root", func() {
+		resp := serve("/linkdir/data.txt")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+		Expect(resp.Body.String()).NotTo(ContainSubstring("secret"))
+	})
+	It("serves files inside of root", func() {
+		resp := serve("/public.txt")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("public"))
+	})
+})
+
+var _ = Describe("FileServerFS", func() {
+	var handler http.Handler
+	BeforeEach(func() {
+		handler = libhttp.FileServerFS(fstest.MapFS{
+			"index.html":    &fstest.MapFile{Data: []byte("<html>index</html>")},
+			"static/app.js": &fstest.MapFile{Data: []byte("console.log('app')")},
+		}, "/")
+	})
+	serve := func(target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		return resp
+	}
+	It("serves existing files", func() {
+		resp := serve("/static/app.js")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(ContainSubstring("javascript"))
+		Expect(resp.Body.String()).To(Equal("console.log('app')"))
+	})
+	It("falls back to index.html for missing routes", func() {
+		resp := serve("/users/42")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("<html>index</html>"))
+	})
+	It("serves the default file at root", func() {
+		resp := serve("/")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("<html>index</html>"))
+	})
+	It("returns 404 for missing assets", func() {
+		handler = libhttp.FileServerFS(fstest.MapFS{
+			"index.html": &fstest.MapFile{Data: []byte("<html>index</html>")},
+		}, "/", libhttp.WithAssetExtensions(".js"))
+		resp := serve("/static/missing.js")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+	})
+})
+
+var _ = Describe("FileServer cache headers", func() {
+	var handler http.Handler
+	BeforeEach(func() {
+		handler = libhttp.FileServerFS(
+			fstest.MapFS{
+				"index.html":        &fstest.MapFile{Data: []byte("index"), ModTime: time.Unix(1700000000, 0)},
+				"static/app.abc.js": &fstest.MapFile{Data: []byte("app"), ModTime: time.Unix(1700000000, 0)},
+			},
+			"/",
+			libhttp.WithCacheControl("/static/*", "max-age=31536000, immutable"),
+			libhttp.WithCacheControl("index.html", "no-cache"),
+		)
+	})
+	serve := func(target string, header http.Header) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+	It("sets cache control for assets", func() {
+		resp := serve("/static/app.abc.js", nil)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Cache-Control")).To(Equal("max-age=31536000, immutable"))
+		Expect(resp.Header().Get("ETag")).NotTo(BeEmpty())
+	})
+	It("sets cache control for index.html fallback", func() {
+		resp := serve("/users/42", nil)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Cache-Control")).To(Equal("no-cache"))
+	})
+	It("returns 304 for matching If-None-Match", func() {
+		etag := serve("/static/app.abc.js", nil).Header().Get("ETag")
+		resp := serve("/static/app.abc.js", http.Header{"If-None-Match": []string{etag}})
+		Expect(resp.Code).To(Equal(http.StatusNotModified))
+		Expect(resp.Body.Len()).To(Equal(0))
+	})
+})
@@ -5,5 +5,11 @@
 package http
 
 const (
-	ContentTypeHeaderName = "Content-Type"
+	ContentTypeHeaderName        = "Content-Type"
+	RequestIDHeaderName          = "X-Request-ID"
+	AcceptHeaderName             = "Accept"
+	ContentDispositionHeaderName = "Content-Disposition"
+	DangerPassphraseHeaderName   = "X-Danger-Passphrase"
+	DangerConfirmationHeaderName = "X-Danger-Confirmation"
+	MethodOverrideHeaderName     = "X-HTTP-Method-Override"
 )
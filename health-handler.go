@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthResponse is the JSON body returned by the health and readiness handlers.
+type HealthResponse struct {
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// NewHealthHandler returns 200 with {"status":"ok"}, e.g. for /healthz.
+func NewHealthHandler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		sendHealthResponse(req.Context(), resp, HealthResponse{Status: HealthStatusOK}, http.StatusOK)
+	})
+}
+
+// NewReadinessHandler runs all checks with the request context, e.g. for /readyz.
+// It returns 200 if all checks pass and 503 with the errors of the failing checks otherwise.
+func NewReadinessHandler(checks ...func(ctx context.Context) error) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		var errs []string
+		for i, check := range checks {
+			if err := check(ctx); err != nil {
+				errs = append(errs, fmt.Sprintf("check %d: %v", i, err))
+			}
+		}
+		if len(errs) > 0 {
+			glog.V(2).Infof("readiness check failed: %v", errs)
+			sendHealthResponse(ctx, resp, HealthResponse{Status: HealthStatusUnavailable, Errors: errs}, http.StatusServiceUnavailable)
+			return
+		}
+		sendHealthResponse(ctx, resp, HealthResponse{Status: HealthStatusOK}, http.StatusOK)
+	})
+}
+
+func sendHealthResponse(ctx context.Context, resp http.ResponseWriter, healthResponse HealthResponse, statusCode int) {
+	if err := SendJSONResponse(ctx, resp, healthResponse, statusCode); err != nil {
+		glog.Warningf("send health response failed: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HealthHandler", func() {
+	It("returns ok", func() {
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		resp := httptest.NewRecorder()
+		libhttp.NewHealthHandler().ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+		Expect(resp.Body.String()).To(Equal(`{"status":"ok"}`))
+	})
+})
+
+var _ = Describe("ReadinessHandler", func() {
+	var ctx context.Context
+	var resp *httptest.ResponseRecorder
+	var healthResponse libhttp.HealthResponse
+	var checks []func(ctx context.Context) error
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+		healthResponse = libhttp.HealthResponse{}
+		checks = nil
+	})
+	JustBeforeEach(func() {
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx)
+		libhttp.NewReadinessHandler(checks...).ServeHTTP(resp, req)
+		Expect(json.NewDecoder(resp.Body).Decode(&healthResponse)).To(Succeed())
+	})
+	Context("all checks pass", func() {
+		BeforeEach(func() {
+			checks = []func(ctx context.Context) error{
+				func(ctx context.Context) error { return nil },
+				func(ctx context.Context) error { return nil },
+			}
+		})
+		It("returns ok", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(healthResponse.Status).To(Equal(libhttp.HealthStatusOK))
+			Expect(healthResponse.Errors).To(BeEmpty())
+		})
+	})
+	Context("one check fails", func() {
+		BeforeEach(func() {
+			checks = []func(ctx context.Context) error{
+				func(ctx context.Context) error { return nil },
+				func(ctx context.Context) error { return errors.New("database down") },
+			}
+		})
+		It("returns unavailable", func() {
+			Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(healthResponse.Status).To(Equal(libhttp.HealthStatusUnavailable))
+			Expect(healthResponse.Errors).To(Equal([]string{"check 1: database down"}))
+		})
+	})
+	Context("canceled context", func() {
+		BeforeEach(func() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			cancel()
+			checks = []func(ctx context.Context) error{
+				func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					default:
+						return nil
+					}
+				},
+			}
+		})
+		It("returns unavailable", func() {
+			Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(healthResponse.Errors).To(Equal([]string{"check 0: context canceled"}))
+		})
+	})
+})
@@ -7,6 +7,7 @@ package http
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	stderrors "errors"
 	"net"
 	"net/http"
@@ -25,22 +26,40 @@ type HttpClientBuilder interface {
 	WithRedirects() HttpClientBuilder
 	WithoutRedirects() HttpClientBuilder
 	WithTimeout(timeout time.Duration) HttpClientBuilder
+	WithRequestTimeout(requestTimeout time.Duration) HttpClientBuilder
 	WithDialFunc(dialFunc DialFunc) HttpClientBuilder
 	WithInsecureSkipVerify(insecureSkipVerify bool) HttpClientBuilder
 	WithClientCert(caCertPath string, clientCertPath string, clientKeyPath string) HttpClientBuilder
+	WithServerName(serverName string) HttpClientBuilder
+	WithRootCAs(rootCAs *x509.CertPool) HttpClientBuilder
+	WithMaxIdleConns(maxIdleConns int) HttpClientBuilder
+	WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) HttpClientBuilder
+	WithIdleConnTimeout(idleConnTimeout time.Duration) HttpClientBuilder
+	WithUserAgent(userAgent string) HttpClientBuilder
+	WithLogging(enabled bool) HttpClientBuilder
+	WithMetrics(metrics RoundTripperMetrics) HttpClientBuilder
 	Build(ctx context.Context) (*http.Client, error)
 	BuildRoundTripper(ctx context.Context) (http.RoundTripper, error)
 }
 
 type httpClientBuilder struct {
-	proxy              Proxy
-	checkRedirect      CheckRedirect
-	timeout            time.Duration
-	dialFunc           DialFunc
-	insecureSkipVerify bool
-	caCertPath         string
-	clientCertPath     string
-	clientKeyPath      string
+	proxy               Proxy
+	checkRedirect       CheckRedirect
+	timeout             time.Duration
+	requestTimeout      time.Duration
+	dialFunc            DialFunc
+	insecureSkipVerify  bool
+	caCertPath          string
+	clientCertPath      string
+	clientKeyPath       string
+	serverName          string
+	rootCAs             *x509.CertPool
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	userAgent           string
+	logging             bool
+	metrics             RoundTripperMetrics
 }
 
 func (h *httpClientBuilder) WithClientCert(caCertPath string, clientCertPath string, clientKeyPath string) HttpClientBuilder {
@@ -50,6 +69,20 @@ func (h *httpClientBuilder) WithClientCert(caCertPath string, clientCertPath str
 	return h
 }
 
+// WithServerName sets the server name used for SNI and certificate verification,
+// e.g. if the client dials an IP address.
+func (h *httpClientBuilder) WithServerName(serverName string) HttpClientBuilder {
+	h.serverName = serverName
+	return h
+}
+
+// WithRootCAs sets the certificate pool used to verify server certificates.
+// It replaces the CA loaded by WithClientCert.
+func (h *httpClientBuilder) WithRootCAs(rootCAs *x509.CertPool) HttpClientBuilder {
+	h.rootCAs = rootCAs
+	return h
+}
+
 type Proxy func(req *http.Request) (*url.URL, error)
 
 type CheckRedirect func(req *http.Request, via []*http.Request) error
@@ -59,14 +92,61 @@ func NewClientBuilder() HttpClientBuilder {
 	b.WithoutProxy()
 	b.WithRedirects()
 	b.WithTimeout(30 * time.Second)
+	b.WithMaxIdleConns(100)
+	b.WithIdleConnTimeout(90 * time.Second)
 	return b
 }
 
+// WithMaxIdleConns limits the idle connections across all hosts, zero means no limit.
+func (h *httpClientBuilder) WithMaxIdleConns(maxIdleConns int) HttpClientBuilder {
+	h.maxIdleConns = maxIdleConns
+	return h
+}
+
+// WithMaxIdleConnsPerHost limits the idle connections per host, zero uses http.DefaultMaxIdleConnsPerHost.
+func (h *httpClientBuilder) WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) HttpClientBuilder {
+	h.maxIdleConnsPerHost = maxIdleConnsPerHost
+	return h
+}
+
+// WithIdleConnTimeout closes idle connections after the given duration, zero means no limit.
+func (h *httpClientBuilder) WithIdleConnTimeout(idleConnTimeout time.Duration) HttpClientBuilder {
+	h.idleConnTimeout = idleConnTimeout
+	return h
+}
+
+// WithUserAgent sets the User-Agent header of requests that have none.
+func (h *httpClientBuilder) WithUserAgent(userAgent string) HttpClientBuilder {
+	h.userAgent = userAgent
+	return h
+}
+
+// WithLogging logs each request with NewRoundTripperLog.
+func (h *httpClientBuilder) WithLogging(enabled bool) HttpClientBuilder {
+	h.logging = enabled
+	return h
+}
+
+// WithMetrics records each request with NewRoundTripperMetrics.
+func (h *httpClientBuilder) WithMetrics(metrics RoundTripperMetrics) HttpClientBuilder {
+	h.metrics = metrics
+	return h
+}
+
+// WithTimeout sets the timeout for establishing a connection.
+// It does not limit the time waiting for the response, see WithRequestTimeout.
 func (h *httpClientBuilder) WithTimeout(timeout time.Duration) HttpClientBuilder {
 	h.timeout = timeout
 	return h
 }
 
+// WithRequestTimeout sets http.Client.Timeout, which limits the whole request
+// including connecting, redirects and reading the response body. Zero means no limit.
+func (h *httpClientBuilder) WithRequestTimeout(requestTimeout time.Duration) HttpClientBuilder {
+	h.requestTimeout = requestTimeout
+	return h
+}
+
 func (h *httpClientBuilder) WithDialFunc(dialFunc DialFunc) HttpClientBuilder {
 	h.dialFunc = dialFunc
 	return h
@@ -94,11 +174,38 @@ func (h *httpClientBuilder) BuildRoundTripper(ctx context.Context) (http.RoundTr
 		}
 	}
 	tlsClientConfig.InsecureSkipVerify = h.insecureSkipVerify
-	return &http.Transport{
-		Proxy:           h.proxy,
-		DialContext:     h.BuildDialFunc(),
-		TLSClientConfig: tlsClientConfig,
-	}, nil
+	if tlsClientConfig.MinVersion == 0 {
+		tlsClientConfig.MinVersion = tls.VersionTLS12
+	}
+	if h.serverName != "" {
+		tlsClientConfig.ServerName = h.serverName
+	}
+	if h.rootCAs != nil {
+		tlsClientConfig.RootCAs = h.rootCAs
+	}
+	return h.wrapRoundTripper(&http.Transport{
+		Proxy:               h.proxy,
+		DialContext:         h.BuildDialFunc(),
+		TLSClientConfig:     tlsClientConfig,
+		MaxIdleConns:        h.maxIdleConns,
+		MaxIdleConnsPerHost: h.maxIdleConnsPerHost,
+		IdleConnTimeout:     h.idleConnTimeout,
+	}), nil
+}
+
+// wrapRoundTripper wraps the transport with the enabled layers.
+// Metrics are the outermost layer, followed by logging and the User-Agent header.
+func (h *httpClientBuilder) wrapRoundTripper(roundTripper http.RoundTripper) http.RoundTripper {
+	if h.userAgent != "" {
+		roundTripper = NewRoundTripperUserAgent(roundTripper, h.userAgent)
+	}
+	if h.logging {
+		roundTripper = NewRoundTripperLog(roundTripper)
+	}
+	if h.metrics != nil {
+		roundTripper = NewRoundTripperMetrics(roundTripper, h.metrics)
+	}
+	return roundTripper
 }
 
 func (h *httpClientBuilder) Build(ctx context.Context) (*http.Client, error) {
@@ -113,6 +220,7 @@ func (h *httpClientBuilder) Build(ctx context.Context) (*http.Client, error) {
 	return &http.Client{
 		Transport:     roundTripper,
 		CheckRedirect: h.checkRedirect,
+		Timeout:       h.requestTimeout,
 	}, nil
 }
 
@@ -148,5 +256,5 @@ func (h *httpClientBuilder) WithoutRedirects() HttpClientBuilder {
 
 func (h *httpClientBuilder) WithInsecureSkipVerify(insecureSkipVerify bool) HttpClientBuilder {
 	h.insecureSkipVerify = insecureSkipVerify
-	return nil
+	return h
 }
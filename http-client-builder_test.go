@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HttpClientBuilder", func() {
+	var ctx context.Context
+	var builder libhttp.HttpClientBuilder
+	BeforeEach(func() {
+		ctx = context.Background()
+		builder = libhttp.NewClientBuilder()
+	})
+	buildTransport := func() *http.Transport {
+		roundTripper, err := builder.BuildRoundTripper(ctx)
+		Expect(err).To(BeNil())
+		transport, ok := roundTripper.(*http.Transport)
+		Expect(ok).To(BeTrue())
+		return transport
+	}
+	It("uses default idle connection settings", func() {
+		transport := buildTransport()
+		Expect(transport.MaxIdleConns).To(Equal(100))
+		Expect(transport.MaxIdleConnsPerHost).To(Equal(0))
+		Expect(transport.IdleConnTimeout).To(Equal(90 * time.Second))
+	})
+	It("sets idle connection settings", func() {
+		builder.WithMaxIdleConns(200).WithMaxIdleConnsPerHost(50).WithIdleConnTimeout(time.Minute)
+		transport := buildTransport()
+		Expect(transport.MaxIdleConns).To(Equal(200))
+		Expect(transport.MaxIdleConnsPerHost).To(Equal(50))
+		Expect(transport.IdleConnTimeout).To(Equal(time.Minute))
+	})
+	It("uses TLS 1.2 as minimum version", func() {
+		transport := buildTransport()
+		Expect(transport.TLSClientConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(transport.TLSClientConfig.ServerName).To(BeEmpty())
+		Expect(transport.TLSClientConfig.RootCAs).To(BeNil())
+	})
+	It("sets server name and root CAs", func() {
+		rootCAs := x509.NewCertPool()
+		builder.WithServerName("api.example.com").WithRootCAs(rootCAs)
+		transport := buildTransport()
+		Expect(transport.TLSClientConfig.ServerName).To(Equal("api.example.com"))
+		Expect(transport.TLSClientConfig.RootCAs).To(BeIdenticalTo(rootCAs))
+	})
+	It("sets insecure skip verify", func() {
+		Expect(builder.WithInsecureSkipVerify(true)).NotTo(BeNil())
+		transport := buildTransport()
+		Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+	})
+	It("builds a client", func() {
+		client, err := builder.WithMaxIdleConnsPerHost(10).Build(ctx)
+		Expect(err).To(BeNil())
+		transport, ok := client.Transport.(*http.Transport)
+		Expect(ok).To(BeTrue())
+		Expect(transport.MaxIdleConnsPerHost).To(Equal(10))
+	})
+	Context("with logging and metrics", func() {
+		var server *httptest.Server
+		var metrics *mocks.HttpRoundTripperMetrics
+		var serverCounter int32
+		BeforeEach(func() {
+			serverCounter = 0
+			server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&serverCounter, 1)
+				resp.WriteHeader(http.StatusTeapot)
+			}))
+			metrics = &mocks.HttpRoundTripperMetrics{}
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+		It("records metrics of requests", func() {
+			client, err := builder.WithLogging(true).WithMetrics(metrics).Build(ctx)
+			Expect(err).To(BeNil())
+			_, isTransport := client.Transport.(*http.Transport)
+			Expect(isTransport).To(BeFalse())
+
+			resp, err := client.Get(server.URL)
+			Expect(err).To(BeNil())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+			Expect(atomic.LoadInt32(&serverCounter)).To(Equal(int32(1)))
+			Expect(metrics.TotalCounterIncCallCount()).To(Equal(1))
+			Expect(metrics.SuccessCounterIncCallCount()).To(Equal(1))
+			_, method, statusCode := metrics.SuccessCounterIncArgsForCall(0)
+			Expect(method).To(Equal(http.MethodGet))
+			Expect(statusCode).To(Equal(http.StatusTeapot))
+		})
+		It("records failures", func() {
+			client, err := builder.WithMetrics(metrics).Build(ctx)
+			Expect(err).To(BeNil())
+			server.Close()
+			_, err = client.Get(server.URL)
+			Expect(err).NotTo(BeNil())
+			Expect(metrics.TotalCounterIncCallCount()).To(Equal(1))
+			Expect(metrics.FailureCounterIncCallCount()).To(Equal(1))
+		})
+	})
+	Context("with user agent", func() {
+		var server *httptest.Server
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				_, _ = resp.Write([]byte(req.UserAgent()))
+			}))
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+		getUserAgent := func(client *http.Client, userAgent string) string {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			Expect(err).To(BeNil())
+			if userAgent != "" {
+				req.Header.Set("User-Agent", userAgent)
+			}
+			resp, err := client.Do(req)
+			Expect(err).To(BeNil())
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			return string(body)
+		}
+		It("sends the configured user agent", func() {
+			client, err := builder.WithUserAgent("my-service/1.0").Build(ctx)
+			Expect(err).To(BeNil())
+			Expect(getUserAgent(client, "")).To(Equal("my-service/1.0"))
+		})
+		It("keeps the user agent of the request", func() {
+			client, err := builder.WithUserAgent("my-service/1.0").Build(ctx)
+			Expect(err).To(BeNil())
+			Expect(getUserAgent(client, "custom/2.0")).To(Equal("custom/2.0"))
+		})
+	})
+	Context("with request timeout", func() {
+		var listener net.Listener
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).To(BeNil())
+			listener := listener
+			go func() {
+				// keep accepted connections open without responding until the listener is closed
+				var conns []net.Conn
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						for _, conn := range conns {
+							_ = conn.Close()
+						}
+						return
+					}
+					conns = append(conns, conn)
+				}
+			}()
+		})
+		AfterEach(func() {
+			listener.Close()
+		})
+		It("has no request timeout by default", func() {
+			client, err := builder.Build(ctx)
+			Expect(err).To(BeNil())
+			Expect(client.Timeout).To(Equal(time.Duration(0)))
+		})
+		It("times out if the server never responds", func() {
+			client, err := builder.WithRequestTimeout(100 * time.Millisecond).Build(ctx)
+			Expect(err).To(BeNil())
+			Expect(client.Timeout).To(Equal(100 * time.Millisecond))
+
+			start := time.Now()
+			_, err = client.Get("http://" + listener.Addr().String())
+			Expect(err).NotTo(BeNil())
+			var netErr net.Error
+			Expect(errors.As(err, &netErr)).To(BeTrue())
+			Expect(netErr.Timeout()).To(BeTrue())
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// IPFilterOptions configures NewIPFilterHandler.
+type IPFilterOptions struct {
+	// TrustedProxies are the proxies whose X-Forwarded-For header is used to find the client IP
+	TrustedProxies []*net.IPNet
+}
+
+// WithTrustedProxies uses X-Forwarded-For if the request comes from one of the proxies.
+func WithTrustedProxies(proxies ...*net.IPNet) func(*IPFilterOptions) {
+	return func(options *IPFilterOptions) {
+		options.TrustedProxies = append(options.TrustedProxies, proxies...)
+	}
+}
+
+// NewIPFilterHandler returns 403 Forbidden if the client IP is in deny or, if allow is not empty, not in allow.
+// Deny takes precedence over allow. The client IP is the RemoteAddr, or if the request comes from
+// a trusted proxy, the last address of X-Forwarded-For that is not a trusted proxy.
+func NewIPFilterHandler(next http.Handler, allow []*net.IPNet, deny []*net.IPNet, optionFns ...func(*IPFilterOptions)) http.Handler {
+	var options IPFilterOptions
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ip := clientIP(req, options.TrustedProxies)
+		if ip == nil || containsIP(deny, ip) || len(allow) > 0 && !containsIP(allow, ip) {
+			glog.V(2).Infof("%s request to %s from %v forbidden", req.Method, req.URL.Path, ip)
+			http.Error(resp, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// clientIP returns the IP of the client or nil if it can't be parsed.
+// An empty or invalid X-Forwarded-For entry of a trusted proxy also returns nil,
+// because the client can't be determined.
+func clientIP(req *http.Request, trustedProxies []*net.IPNet) net.IP {
+	ip := parseIP(req.RemoteAddr)
+	if ip == nil || !containsIP(trustedProxies, ip) {
+		return ip
+	}
+	values := req.Header.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		return ip
+	}
+	forwardedFor := strings.Split(strings.Join(values, ","), ",")
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		forwardedIP := parseIP(strings.TrimSpace(forwardedFor[i]))
+		if forwardedIP == nil {
+			return nil
+		}
+		ip = forwardedIP
+		if !containsIP(trustedProxies, ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
+// parseIP parses an IP with or without port.
+func parseIP(value string) net.IP {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	return net.ParseIP(value)
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IPFilterHandler", func() {
+	parseCIDR := func(cidr string) *net.IPNet {
+		_, network, err := net.ParseCIDR(cidr)
+		Expect(err).To(BeNil())
+		return network
+	}
+	var allow []*net.IPNet
+	var deny []*net.IPNet
+	var optionFns []func(*libhttp.IPFilterOptions)
+	BeforeEach(func() {
+		allow = []*net.IPNet{parseCIDR("10.0.0.0/8")}
+		deny = []*net.IPNet{parseCIDR("10.0.0.13/32")}
+		optionFns = nil
+	})
+	serve := func(remoteAddr string, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		resp := httptest.NewRecorder()
+		libhttp.NewIPFilterHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}), allow, deny, optionFns...).ServeHTTP(resp, req)
+		return resp.Code
+	}
+	It("allows IPs in an allowed CIDR", func() {
+		Expect(serve("10.1.2.3:1234", "")).To(Equal(http.StatusOK))
+	})
+	It("forbids IPs outside of the allowed CIDRs", func() {
+		Expect(serve("192.168.1.1:1234", "")).To(Equal(http.StatusForbidden))
+	})
+	It("forbids denied IPs even if allowed", func() {
+		Expect(serve("10.0.0.13:1234", "")).To(Equal(http.StatusForbidden))
+	})
+	It("allows all not denied IPs if allow is empty", func() {
+		allow = nil
+		Expect(serve("192.168.1.1:1234", "")).To(Equal(http.StatusOK))
+		Expect(serve("10.0.0.13:1234", "")).To(Equal(http.StatusForbidden))
+	})
+	It("ignores X-Forwarded-For of untrusted clients", func() {
+		Expect(serve("192.168.1.1:1234", "10.1.2.3")).To(Equal(http.StatusForbidden))
+	})
+	Context("with trusted proxy", func() {
+		BeforeEach(func() {
+			optionFns = append(optionFns, libhttp.WithTrustedProxies(parseCIDR("172.16.0.0/12")))
+		})
+		It("uses the client of X-Forwarded-For", func() {
+			Expect(serve("172.16.0.1:1234", "10.1.2.3")).To(Equal(http.StatusOK))
+			Expect(serve("172.16.0.1:1234", "10.0.0.13")).To(Equal(http.StatusForbidden))
+		})
+		It("uses the last untrusted address", func() {
+			Expect(serve("172.16.0.1:1234", "10.1.2.3, 192.168.1.1, 172.16.0.2")).To(Equal(http.StatusForbidden))
+			Expect(serve("172.16.0.1:1234", "192.168.1.1, 10.1.2.3, 172.16.0.2")).To(Equal(http.StatusOK))
+		})
+		It("uses the proxy without X-Forwarded-For", func() {
+			allow = nil
+			Expect(serve("172.16.0.1:1234", "")).To(Equal(http.StatusOK))
+		})
+		DescribeTable("forbids invalid X-Forwarded-For entries instead of using the proxy",
+			func(forwardedFor string) {
+				allow = nil
+				Expect(serve("172.16.0.1:1234", forwardedFor)).To(Equal(http.StatusForbidden))
+			},
+			Entry("unparseable", "banana"),
+			Entry("empty entry", "10.1.2.3, , 172.16.0.2"),
+			Entry("trailing comma", "10.1.2.3,"),
+			Entry("only commas", ",,"),
+		)
+	})
+})
@@ -0,0 +1,28 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/errors"
+)
+
+// NewJSONBodyHandler decodes the JSON request body into In with DecodeJSONRequest,
+// calls fn and encodes the returned Out as JSON.
+// Decode errors are returned with ErrorCodeValidation, use NewJSONErrorHandler to send them as JSON.
+func NewJSONBodyHandler[In, Out any](
+	fn func(ctx context.Context, in In) (Out, error),
+	optionFns ...func(*DecodeJSONRequestOptions),
+) WithError {
+	return NewJsonHandler(JsonHandlerFunc(func(ctx context.Context, req *http.Request) (interface{}, error) {
+		in, err := DecodeJSONRequest[In](ctx, req, optionFns...)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode request failed")
+		}
+		return fn(ctx, in)
+	}))
+}
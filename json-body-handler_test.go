@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+type greetResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+var _ = Describe("JSONBodyHandler", func() {
+	var resp *httptest.ResponseRecorder
+	var body string
+	var handler http.Handler
+	BeforeEach(func() {
+		handler = libhttp.NewJSONErrorHandler(libhttp.NewJSONBodyHandler(func(ctx context.Context, in greetRequest) (greetResponse, error) {
+			if in.Name == "unknown" {
+				return greetResponse{}, libhttp.WrapWithCode(errors.New(ctx, "user not found"), libhttp.ErrorCodeNotFound, http.StatusNotFound)
+			}
+			return greetResponse{Greeting: "hello " + in.Name}, nil
+		}))
+	})
+	JustBeforeEach(func() {
+		req := httptest.NewRequest(http.MethodPost, "/greet", strings.NewReader(body))
+		req.Header.Set(libhttp.ContentTypeHeaderName, libhttp.ApplicationJsonContentType)
+		resp = httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+	})
+	errorCode := func() string {
+		var errorResponse libhttp.ErrorResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+		return errorResponse.Error.Code
+	}
+	Context("valid body", func() {
+		BeforeEach(func() {
+			body = `{"name":"world"}`
+		})
+		It("encodes the result", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(Equal("{\"greeting\":\"hello world\"}\n"))
+		})
+	})
+	Context("invalid json", func() {
+		BeforeEach(func() {
+			body = `{"name":`
+		})
+		It("returns a validation error", func() {
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			Expect(errorCode()).To(Equal(libhttp.ErrorCodeValidation))
+		})
+	})
+	Context("domain error", func() {
+		BeforeEach(func() {
+			body = `{"name":"unknown"}`
+		})
+		It("returns the error of the handler", func() {
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+			Expect(errorCode()).To(Equal(libhttp.ErrorCodeNotFound))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+// NewJSONErrorHandler is like NewErrorHandler but writes errors as JSON ErrorResponse.
+// With HideInternalMessages 5xx errors are sent with ErrorCodeInternal and InternalErrorMessage only.
+func NewJSONErrorHandler(handlerWithError WithError, optionFns ...func(*ErrorHandlerOptions)) http.Handler {
+	options := buildErrorHandlerOptions(optionFns...)
+	return newErrorHandler(handlerWithError, func(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error) {
+		sendJSONErrorWithOptions(ctx, resp, req, err, options)
+	})
+}
+
+func sendJSONError(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error) {
+	sendJSONErrorWithOptions(ctx, resp, req, err, ErrorHandlerOptions{})
+}
+
+func sendJSONErrorWithOptions(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error, options ErrorHandlerOptions) {
+	code, statusCode, details := ResolveError(err)
+	logRequestError(ctx, statusCode, code, err)
+	errorDetails := ErrorDetails{
+		Code:    code,
+		Message: err.Error(),
+		Details: details,
+	}
+	if hideErrorMessage(statusCode, options) {
+		errorDetails = ErrorDetails{
+			Code:    ErrorCodeInternal,
+			Message: InternalErrorMessage,
+		}
+	}
+	if err := SendJSONResponse(
+		ctx,
+		resp,
+		ErrorResponse{
+			Error: errorDetails,
+		},
+		statusCode,
+	); err != nil {
+		glog.Warningf("send error response failed: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONErrorHandler", func() {
+	var err error
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	var handlerErr error
+	var optionFns []func(*libhttp.ErrorHandlerOptions)
+	var logBuf *bufferRequestLogger
+	BeforeEach(func() {
+		handlerErr = nil
+		optionFns = nil
+		logBuf = &bufferRequestLogger{}
+		req, err = http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		req = req.WithContext(libhttp.ContextWithLogger(req.Context(), logBuf))
+	})
+	JustBeforeEach(func() {
+		resp = httptest.NewRecorder()
+		libhttp.NewJSONErrorHandler(libhttp.WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+			if handlerErr != nil {
+				return handlerErr
+			}
+			return libhttp.SendJSONResponse(ctx, resp, map[string]string{"hello": "world"}, http.StatusOK)
+		}), optionFns...).ServeHTTP(resp, req)
+	})
+	Context("success", func() {
+		It("returns status code 200", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+		It("returns body", func() {
+			Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+		})
+	})
+	Context("coded error", func() {
+		BeforeEach(func() {
+			handlerErr = libhttp.WrapWithCode(errors.New(context.Background(), "banana"), libhttp.ErrorCodeValidation, http.StatusBadRequest)
+		})
+		It("returns status code", func() {
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		})
+		It("returns error response", func() {
+			var errorResponse libhttp.ErrorResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+			Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeValidation))
+			Expect(errorResponse.Error.Message).To(Equal("banana"))
+		})
+	})
+	Context("with hidden internal messages", func() {
+		BeforeEach(func() {
+			optionFns = append(optionFns, libhttp.WithHideInternalMessages())
+		})
+		Context("internal error", func() {
+			BeforeEach(func() {
+				handlerErr = errors.AddDataToError(
+					libhttp.WrapWithCode(errors.New(context.Background(), "open /etc/secret failed"), "STORAGE", http.StatusInternalServerError),
+					map[string]string{"path": "/etc/secret"},
+				)
+			})
+			It("returns a generic error response", func() {
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				var errorResponse libhttp.ErrorResponse
+				Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+				Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeInternal))
+				Expect(errorResponse.Error.Message).To(Equal(libhttp.InternalErrorMessage))
+				Expect(errorResponse.Error.Details).To(BeEmpty())
+			})
+			It("logs the full error", func() {
+				Expect(logBuf.String()).To(ContainSubstring("open /etc/secret failed"))
+			})
+		})
+		Context("client error", func() {
+			BeforeEach(func() {
+				handlerErr = libhttp.WrapWithCode(errors.New(context.Background(), "banana"), libhttp.ErrorCodeValidation, http.StatusBadRequest)
+			})
+			It("returns the error message", func() {
+				Expect(resp.Code).To(Equal(http.StatusBadRequest))
+				var errorResponse libhttp.ErrorResponse
+				Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+				Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeValidation))
+				Expect(errorResponse.Error.Message).To(Equal("banana"))
+			})
+		})
+	})
+})
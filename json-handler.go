@@ -5,6 +5,7 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -22,16 +23,44 @@ func (j JsonHandlerFunc) ServeHTTP(ctx context.Context, req *http.Request) (inte
 	return j(ctx, req)
 }
 
+// JSONResult can be returned by a JsonHandler to respond with a status code other than 200.
+// Body is not written for status 204 No Content. A nil *JSONResult responds with 204 No Content.
+type JSONResult struct {
+	StatusCode int
+	Body       any
+}
+
+// NewJsonHandler encodes the result of the JsonHandler as JSON with status 200,
+// or with the status code of a returned JSONResult.
 func NewJsonHandler(jsonHandler JsonHandler) WithError {
 	return WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
 		result, err := jsonHandler.ServeHTTP(ctx, req)
 		if err != nil {
 			return errors.Wrapf(ctx, err, "json handler failed")
 		}
-		resp.Header().Add(ContentTypeHeaderName, ApplicationJsonContentType)
-		if err := json.NewEncoder(resp).Encode(result); err != nil {
+		statusCode := http.StatusOK
+		switch jsonResult := result.(type) {
+		case JSONResult:
+			statusCode, result = jsonResult.StatusCode, jsonResult.Body
+		case *JSONResult:
+			if jsonResult == nil {
+				statusCode = http.StatusNoContent
+				break
+			}
+			statusCode, result = jsonResult.StatusCode, jsonResult.Body
+		}
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		if statusCode == http.StatusNoContent {
+			resp.WriteHeader(statusCode)
+			return nil
+		}
+		// encode before writing, so an encode error can still be sent as clean error response
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(result); err != nil {
 			return errors.Wrapf(ctx, err, "encode json failed")
 		}
-		return nil
+		return writeJSONResponse(ctx, resp, buf.Bytes(), statusCode)
 	})
 }
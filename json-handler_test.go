@@ -5,8 +5,11 @@
 package http_test
 
 import (
+	"bytes"
 	"context"
 	stderrors "errors"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
 
@@ -58,5 +61,92 @@ var _ = Describe("JsonHandler", func() {
 				Expect(err).NotTo(BeNil())
 			})
 		})
+		Context("content type set by outer handler", func() {
+			BeforeEach(func() {
+				jsonHandler = libhttp.JsonHandlerFunc(func(ctx context.Context, req *http.Request) (interface{}, error) {
+					return "hello", nil
+				})
+			})
+			It("has exactly one content type", func() {
+				resp := httptest.NewRecorder()
+				resp.Header().Set(libhttp.ContentTypeHeaderName, "text/plain")
+				Expect(libhttp.NewJsonHandler(jsonHandler).ServeHTTP(ctx, resp, req)).To(Succeed())
+				Expect(resp.Result().Header.Values(libhttp.ContentTypeHeaderName)).To(Equal([]string{libhttp.ApplicationJsonContentType}))
+			})
+		})
+		Context("unencodable result", func() {
+			BeforeEach(func() {
+				jsonHandler = libhttp.JsonHandlerFunc(func(ctx context.Context, req *http.Request) (interface{}, error) {
+					return map[string]interface{}{
+						"channel": make(chan int),
+					}, nil
+				})
+			})
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+			It("writes nothing", func() {
+				Expect(resp.Body.Len()).To(Equal(0))
+				Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(BeEmpty())
+			})
+			It("sends a single clean error response", func() {
+				var logBuf bytes.Buffer
+				server := httptest.NewUnstartedServer(libhttp.NewErrorHandler(libhttp.NewJsonHandler(jsonHandler)))
+				server.Config.ErrorLog = log.New(&logBuf, "", 0)
+				server.Start()
+				defer server.Close()
+
+				response, err := http.Get(server.URL)
+				Expect(err).To(BeNil())
+				defer response.Body.Close()
+				body, err := io.ReadAll(response.Body)
+				Expect(err).To(BeNil())
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(string(body)).To(HavePrefix("request failed: "))
+				Expect(logBuf.String()).NotTo(ContainSubstring("superfluous"))
+			})
+		})
+		Context("created result", func() {
+			BeforeEach(func() {
+				jsonHandler = libhttp.JsonHandlerFunc(func(ctx context.Context, req *http.Request) (interface{}, error) {
+					return libhttp.JSONResult{
+						StatusCode: http.StatusCreated,
+						Body:       map[string]string{"id": "42"},
+					}, nil
+				})
+			})
+			It("returns status 201 with body", func() {
+				Expect(err).To(BeNil())
+				Expect(resp.Code).To(Equal(http.StatusCreated))
+				Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+				Expect(resp.Body.String()).To(Equal("{\"id\":\"42\"}\n"))
+			})
+		})
+		Context("no content result", func() {
+			BeforeEach(func() {
+				jsonHandler = libhttp.JsonHandlerFunc(func(ctx context.Context, req *http.Request) (interface{}, error) {
+					return &libhttp.JSONResult{StatusCode: http.StatusNoContent}, nil
+				})
+			})
+			It("returns status 204 without body", func() {
+				Expect(err).To(BeNil())
+				Expect(resp.Code).To(Equal(http.StatusNoContent))
+				Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(BeEmpty())
+				Expect(resp.Body.Len()).To(Equal(0))
+			})
+		})
+		Context("nil result pointer", func() {
+			BeforeEach(func() {
+				jsonHandler = libhttp.JsonHandlerFunc(func(ctx context.Context, req *http.Request) (interface{}, error) {
+					var result *libhttp.JSONResult
+					return result, nil
+				})
+			})
+			It("returns status 204 without body", func() {
+				Expect(err).To(BeNil())
+				Expect(resp.Code).To(Equal(http.StatusNoContent))
+				Expect(resp.Body.Len()).To(Equal(0))
+			})
+		})
 	})
 })
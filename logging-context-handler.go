@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+// LoggerContextKey is the context key the request logger is stored under.
+const LoggerContextKey contextKey = "logger"
+
+// RequestLogger writes log messages of a single request.
+type RequestLogger interface {
+	// Infof writes the message if the glog verbosity level is enabled
+	Infof(level glog.Level, format string, args ...interface{})
+	// Warningf writes the message as warning
+	Warningf(format string, args ...interface{})
+}
+
+// GlogRequestLogger writes the messages to glog with Prefix in front.
+type GlogRequestLogger struct {
+	Prefix string
+}
+
+func (g GlogRequestLogger) Infof(level glog.Level, format string, args ...interface{}) {
+	if glog.V(level) {
+		glog.InfoDepth(1, g.Prefix+fmt.Sprintf(format, args...))
+	}
+}
+
+func (g GlogRequestLogger) Warningf(format string, args ...interface{}) {
+	glog.WarningDepth(1, g.Prefix+fmt.Sprintf(format, args...))
+}
+
+// NoopRequestLogger discards all messages.
+type NoopRequestLogger struct{}
+
+func (n NoopRequestLogger) Infof(level glog.Level, format string, args ...interface{}) {}
+
+func (n NoopRequestLogger) Warningf(format string, args ...interface{}) {}
+
+// NewLoggingContextHandler stores a GlogRequestLogger with the method, path and request ID of the request
+// as prefix in the request context. Use it inside NewRequestIDHandler to include the request ID.
+func NewLoggingContextHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		prefix := fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+		if requestID, ok := RequestIDFromContext(ctx); ok {
+			prefix += " request_id=" + requestID
+		}
+		next.ServeHTTP(resp, req.WithContext(ContextWithLogger(ctx, GlogRequestLogger{Prefix: prefix + ": "})))
+	})
+}
+
+// ContextWithLogger returns a copy of ctx that carries the logger.
+func ContextWithLogger(ctx context.Context, logger RequestLogger) context.Context {
+	return context.WithValue(ctx, LoggerContextKey, logger)
+}
+
+// LoggerFromContext returns the logger stored by NewLoggingContextHandler
+// or a NoopRequestLogger if the context carries none.
+func LoggerFromContext(ctx context.Context) RequestLogger {
+	if logger, ok := ctx.Value(LoggerContextKey).(RequestLogger); ok {
+		return logger
+	}
+	return NoopRequestLogger{}
+}
+
+// logRequestError logs a failed request, 5xx errors as warning and others at verbosity 1.
+// Without request logger in the context the error is written to glog, so it is never lost.
+func logRequestError(ctx context.Context, statusCode int, code string, err error) {
+	logger, ok := ctx.Value(LoggerContextKey).(RequestLogger)
+	if !ok {
+		logger = GlogRequestLogger{}
+	}
+	if statusCode >= http.StatusInternalServerError {
+		logger.Warningf("request failed with status %d and code %s: %v", statusCode, code, err)
+		return
+	}
+	logger.Infof(1, "request failed with status %d and code %s: %v", statusCode, code, err)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	"github.com/golang/glog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoggingContextHandler", func() {
+	serve := func(handler http.Handler) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set(libhttp.RequestIDHeaderName, "abc123")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	It("returns the enriched logger inside the handler", func() {
+		var logger libhttp.RequestLogger
+		serve(libhttp.NewRequestIDHandler(libhttp.NewLoggingContextHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			logger = libhttp.LoggerFromContext(req.Context())
+		}))))
+		Expect(logger).To(Equal(libhttp.GlogRequestLogger{Prefix: "POST /orders request_id=abc123: "}))
+	})
+	It("returns a no-op logger outside", func() {
+		Expect(libhttp.LoggerFromContext(context.Background())).To(Equal(libhttp.NoopRequestLogger{}))
+	})
+	It("is used by the JSON error handler", func() {
+		logger := &bufferRequestLogger{}
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req = req.WithContext(libhttp.ContextWithLogger(req.Context(), logger))
+		libhttp.NewJSONErrorHandler(libhttp.WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+			return errors.New(ctx, "banana")
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		Expect(logger.String()).To(ContainSubstring("WARNING request failed with status 500 and code INTERNAL_ERROR: banana"))
+	})
+})
+
+// bufferRequestLogger records all messages with their level.
+type bufferRequestLogger struct {
+	bytes.Buffer
+}
+
+func (b *bufferRequestLogger) Infof(level glog.Level, format string, args ...interface{}) {
+	fmt.Fprintf(&b.Buffer, "V%d %s\n", level, fmt.Sprintf(format, args...))
+}
+
+func (b *bufferRequestLogger) Warningf(format string, args ...interface{}) {
+	fmt.Fprintf(&b.Buffer, "WARNING %s\n", fmt.Sprintf(format, args...))
+}
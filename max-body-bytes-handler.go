@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+// NewMaxBodyBytesHandler limits the request body to maxBytes.
+// If the handler reads beyond the limit, its response including the headers it set is discarded
+// and a JSON 413 is written instead.
+func NewMaxBodyBytesHandler(next http.Handler, maxBytes int64) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > maxBytes {
+			sendPayloadTooLarge(resp, req, maxBytes)
+			return
+		}
+		body := &maxBodyBytesReader{
+			ReadCloser: http.MaxBytesReader(resp, req.Body, maxBytes),
+		}
+		req.Body = body
+		writer := &maxBodyBytesWriter{
+			ResponseWriter: resp,
+			body:           body,
+		}
+		header := resp.Header().Clone()
+		next.ServeHTTP(writer, req)
+		if body.exceeded && !writer.wroteHeader {
+			resetHeader(resp.Header(), header)
+			sendPayloadTooLarge(resp, req, maxBytes)
+		}
+	})
+}
+
+// resetHeader restores header to the given snapshot, e.g. to remove Content-Length set by a discarded response.
+func resetHeader(header http.Header, snapshot http.Header) {
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range snapshot {
+		header[key] = values
+	}
+}
+
+func sendPayloadTooLarge(resp http.ResponseWriter, req *http.Request, maxBytes int64) {
+	glog.V(2).Infof("%s request to %s exceeds body limit of %d bytes", req.Method, req.URL.Path, maxBytes)
+	if err := SendJSONResponse(
+		req.Context(),
+		resp,
+		ErrorResponse{
+			Error: ErrorDetails{
+				Code:    ErrorCodePayloadTooLarge,
+				Message: "request body too large",
+				Details: map[string]any{
+					"max_bytes": maxBytes,
+				},
+			},
+		},
+		http.StatusRequestEntityTooLarge,
+	); err != nil {
+		glog.Warningf("send error response failed: %v", err)
+	}
+}
+
+type maxBodyBytesReader struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (m *maxBodyBytesReader) Read(p []byte) (int, error) {
+	n, err := m.ReadCloser.Read(p)
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		m.exceeded = true
+	}
+	return n, err
+}
+
+// maxBodyBytesWriter discards the handler response once the body limit was exceeded.
+type maxBodyBytesWriter struct {
+	http.ResponseWriter
+	body        *maxBodyBytesReader
+	wroteHeader bool
+}
+
+func (m *maxBodyBytesWriter) WriteHeader(statusCode int) {
+	if m.body.exceeded || m.wroteHeader {
+		return
+	}
+	m.wroteHeader = true
+	m.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (m *maxBodyBytesWriter) Write(p []byte) (int, error) {
+	if m.body.exceeded && !m.wroteHeader {
+		return len(p), nil
+	}
+	m.wroteHeader = true
+	return m.ResponseWriter.Write(p)
+}
+
+// Flush flushes the underlying writer if it supports it, unless the response is discarded.
+func (m *maxBodyBytesWriter) Flush() {
+	if m.body.exceeded && !m.wroteHeader {
+		return
+	}
+	if flusher, ok := m.ResponseWriter.(http.Flusher); ok {
+		m.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (m *maxBodyBytesWriter) Unwrap() http.ResponseWriter {
+	return m.ResponseWriter
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaxBodyBytesHandler", func() {
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	var body io.Reader
+	var contentLength int64
+	var handler http.Handler
+	BeforeEach(func() {
+		contentLength = -1
+		handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			content, err := io.ReadAll(req.Body)
+			if err != nil {
+				http.Error(resp, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(resp, "read %d bytes", len(content))
+		})
+	})
+	JustBeforeEach(func() {
+		req = httptest.NewRequest(http.MethodPost, "http://example.com", body)
+		req.ContentLength = contentLength
+		resp = httptest.NewRecorder()
+		libhttp.NewMaxBodyBytesHandler(handler, 10).ServeHTTP(resp, req)
+	})
+	Context("body under limit", func() {
+		BeforeEach(func() {
+			body = strings.NewReader("banana")
+		})
+		It("returns status code 200", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+		It("returns handler body", func() {
+			Expect(resp.Body.String()).To(Equal("read 6 bytes"))
+		})
+	})
+	Context("body over limit", func() {
+		BeforeEach(func() {
+			body = strings.NewReader("banana banana banana")
+		})
+		It("returns status code 413", func() {
+			Expect(resp.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+		It("returns error response", func() {
+			var errorResponse libhttp.ErrorResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+			Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodePayloadTooLarge))
+		})
+	})
+	Context("handler sets headers before reading a body over limit", func() {
+		BeforeEach(func() {
+			body = strings.NewReader("banana banana banana")
+			handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				resp.Header().Set(libhttp.ContentTypeHeaderName, libhttp.TextCSVContentType)
+				resp.Header().Set("Content-Length", "5")
+				resp.Header().Set("X-Banana", "yes")
+				_, _ = io.ReadAll(req.Body)
+				fmt.Fprint(resp, "a,b,c")
+			})
+		})
+		It("returns status code 413", func() {
+			Expect(resp.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+		It("replaces the headers of the handler", func() {
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+			Expect(resp.Header().Get("Content-Length")).To(BeEmpty())
+			Expect(resp.Header().Get("X-Banana")).To(BeEmpty())
+		})
+		It("returns error response", func() {
+			var errorResponse libhttp.ErrorResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+			Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodePayloadTooLarge))
+		})
+	})
+	Context("content length over limit", func() {
+		BeforeEach(func() {
+			body = strings.NewReader("banana banana banana")
+			contentLength = 20
+		})
+		It("returns status code 413", func() {
+			Expect(resp.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+	})
+	Context("flushing handler", func() {
+		var flushErr error
+		BeforeEach(func() {
+			body = strings.NewReader("banana")
+			handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				fmt.Fprint(resp, "first")
+				flushErr = http.NewResponseController(resp).Flush()
+			})
+		})
+		It("flushes without error", func() {
+			Expect(flushErr).To(BeNil())
+		})
+		It("flushes underlying writer", func() {
+			Expect(resp.Flushed).To(BeTrue())
+		})
+		It("returns handler body", func() {
+			Expect(resp.Body.String()).To(Equal("first"))
+		})
+	})
+})
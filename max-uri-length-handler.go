@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MaxURILengthOptions configures NewMaxURILengthHandler.
+type MaxURILengthOptions struct {
+	// MaxQueryParams limits the number of query parameters, zero means no limit
+	MaxQueryParams int
+}
+
+// WithMaxQueryParams limits the number of query parameters to mitigate hash flooding.
+func WithMaxQueryParams(maxQueryParams int) func(*MaxURILengthOptions) {
+	return func(options *MaxURILengthOptions) {
+		options.MaxQueryParams = maxQueryParams
+	}
+}
+
+// NewMaxURILengthHandler responds a JSON 414 URI Too Long if the request URI exceeds maxBytes.
+// With WithMaxQueryParams requests with more query parameters are rejected with a JSON 400,
+// the parameters are counted without parsing the query.
+func NewMaxURILengthHandler(next http.Handler, maxBytes int, optionFns ...func(*MaxURILengthOptions)) http.Handler {
+	var options MaxURILengthOptions
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		requestURI := req.RequestURI
+		if requestURI == "" {
+			requestURI = req.URL.RequestURI()
+		}
+		if len(requestURI) > maxBytes {
+			sendJSONError(ctx, resp, req, WrapWithDetailsf(
+				ctx,
+				ErrorCodeURITooLong,
+				http.StatusRequestURITooLong,
+				map[string]any{"max_bytes": maxBytes},
+				"request uri too long",
+			))
+			return
+		}
+		if options.MaxQueryParams > 0 && tooManyQueryParams(req.URL.RawQuery, options.MaxQueryParams) {
+			sendJSONError(ctx, resp, req, WrapWithDetailsf(
+				ctx,
+				ErrorCodeValidation,
+				http.StatusBadRequest,
+				map[string]any{"max_query_params": options.MaxQueryParams},
+				"too many query parameters",
+			))
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// tooManyQueryParams returns true if the raw query has more than limit non-empty parameters.
+// It stops at the first parameter above the limit without splitting the whole query.
+func tooManyQueryParams(rawQuery string, limit int) bool {
+	var counter int
+	for rawQuery != "" {
+		var param string
+		param, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if param == "" {
+			continue
+		}
+		counter++
+		if counter > limit {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaxURILengthHandler", func() {
+	var nextCounter int
+	var handler http.Handler
+	BeforeEach(func() {
+		nextCounter = 0
+		handler = libhttp.NewMaxURILengthHandler(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				nextCounter++
+			}),
+			64,
+			libhttp.WithMaxQueryParams(3),
+		)
+	})
+	serve := func(target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		return resp
+	}
+	decode := func(resp *httptest.ResponseRecorder) libhttp.ErrorResponse {
+		var errorResponse libhttp.ErrorResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+		return errorResponse
+	}
+	It("passes normal requests", func() {
+		resp := serve("http://example.com/users?a=1&b=2")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(nextCounter).To(Equal(1))
+	})
+	It("rejects a too long uri", func() {
+		resp := serve("http://example.com/users?q=" + strings.Repeat("a", 64))
+		Expect(resp.Code).To(Equal(http.StatusRequestURITooLong))
+		Expect(nextCounter).To(Equal(0))
+		errorResponse := decode(resp)
+		Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeURITooLong))
+		Expect(errorResponse.Error.Details).To(HaveKeyWithValue("max_bytes", BeNumerically("==", 64)))
+	})
+	It("rejects too many query parameters", func() {
+		resp := serve("http://example.com/users?a=1&b=2&c=3&d=4")
+		Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		Expect(nextCounter).To(Equal(0))
+		errorResponse := decode(resp)
+		Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeValidation))
+		Expect(errorResponse.Error.Message).To(Equal("too many query parameters"))
+	})
+	It("ignores empty query parameters", func() {
+		resp := serve("http://example.com/users?a=1&&b=2&c=3&")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(nextCounter).To(Equal(1))
+	})
+})
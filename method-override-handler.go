@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// MethodOverrideFormField is the form field NewMethodOverrideHandler reads the method from.
+const MethodOverrideFormField = "_method"
+
+// NewMethodOverrideHandler lets clients like HTML forms send PUT, PATCH and DELETE as POST.
+// The method is taken from the X-HTTP-Method-Override header or the _method field of an
+// application/x-www-form-urlencoded body. Other methods and requests other than POST are passed unchanged.
+func NewMethodOverrideHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		method := req.Header.Get(MethodOverrideHeaderName)
+		if method == "" && IsContentType(req.Header.Get(ContentTypeHeaderName), FormURLEncodedContentType) {
+			if err := req.ParseForm(); err != nil {
+				glog.V(2).Infof("parse form failed: %v", err)
+			}
+			method = req.PostForm.Get(MethodOverrideFormField)
+		}
+		method = strings.ToUpper(method)
+		switch method {
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			glog.V(3).Infof("override method of request to %s with %s", req.URL.Path, method)
+			req = req.Clone(req.Context())
+			req.Method = method
+		case "":
+		default:
+			glog.V(2).Infof("ignore invalid method override %q", method)
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MethodOverrideHandler", func() {
+	var method string
+	var name string
+	serve := func(req *http.Request) {
+		libhttp.NewMethodOverrideHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			method = req.Method
+			name = req.FormValue("name")
+		})).ServeHTTP(httptest.NewRecorder(), req)
+	}
+	formRequest := func(method string, body string) *http.Request {
+		req := httptest.NewRequest(method, "http://example.com/users/1", strings.NewReader(body))
+		req.Header.Set(libhttp.ContentTypeHeaderName, libhttp.FormURLEncodedContentType)
+		return req
+	}
+	BeforeEach(func() {
+		method = ""
+		name = ""
+	})
+	It("overrides the method with the header", func() {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/users/1", nil)
+		req.Header.Set(libhttp.MethodOverrideHeaderName, "delete")
+		serve(req)
+		Expect(method).To(Equal(http.MethodDelete))
+	})
+	It("overrides the method with the form field", func() {
+		serve(formRequest(http.MethodPost, "_method=PUT&name=alice"))
+		Expect(method).To(Equal(http.MethodPut))
+		Expect(name).To(Equal("alice"))
+	})
+	It("ignores methods not allowed", func() {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/users/1", nil)
+		req.Header.Set(libhttp.MethodOverrideHeaderName, "CONNECT")
+		serve(req)
+		Expect(method).To(Equal(http.MethodPost))
+	})
+	It("ignores requests other than POST", func() {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+		req.Header.Set(libhttp.MethodOverrideHeaderName, http.MethodDelete)
+		serve(req)
+		Expect(method).To(Equal(http.MethodGet))
+	})
+	It("keeps POST without override", func() {
+		serve(formRequest(http.MethodPost, "name=alice"))
+		Expect(method).To(Equal(http.MethodPost))
+		Expect(name).To(Equal("alice"))
+	})
+})
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewMetricsHandler records request count, in-flight requests and request duration
+// labeled by method, path and status code.
+// The path is the route template if the handler is used as gorilla/mux middleware (router.Use)
+// and "unmatched" otherwise, so arbitrary URLs can not blow up the label cardinality.
+// Like prometheus.MustRegister it panics if the metrics can not be registered at reg,
+// registering them twice at the same reg is allowed.
+func NewMetricsHandler(next http.Handler, reg prometheus.Registerer) http.Handler {
+	requestsTotal := registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "requests_total",
+		Help:      "Total number of handled http requests.",
+	}, []string{"method", "path", "status"}))
+	requestsInFlight := registerCollector(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "requests_in_flight",
+		Help:      "Number of http requests currently handled.",
+	}, []string{"method", "path"}))
+	requestDuration := registerCollector(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of handled http requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "status"}))
+
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		path := routePath(req)
+		inFlight := requestsInFlight.WithLabelValues(req.Method, path)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		recorder := NewResponseRecorder(resp)
+		next.ServeHTTP(recorder, req)
+
+		status := strconv.Itoa(recorder.StatusCode())
+		requestsTotal.WithLabelValues(req.Method, path, status).Inc()
+		requestDuration.WithLabelValues(req.Method, path, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// registerCollector registers the collector and returns the already registered one on duplicate registration.
+// Any other registration error panics, like prometheus.MustRegister.
+func registerCollector[C prometheus.Collector](reg prometheus.Registerer, collector C) C {
+	if err := reg.Register(collector); err != nil {
+		if alreadyRegistered, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := alreadyRegistered.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
+
+// unmatchedRoutePath is the path label of requests without a gorilla/mux route.
+const unmatchedRoutePath = "unmatched"
+
+func routePath(req *http.Request) string {
+	if route := mux.CurrentRoute(req); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return unmatchedRoutePath
+}
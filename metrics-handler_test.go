@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("MetricsHandler", func() {
+	var registry *prometheus.Registry
+	var handler http.Handler
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		handler = libhttp.NewMetricsHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/missing" {
+				http.NotFound(resp, req)
+				return
+			}
+			_, _ = resp.Write([]byte("ok"))
+		}), registry)
+	})
+	It("counts requests by status", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		Expect(counterValue(registry, "http_server_requests_total", map[string]string{"method": "GET", "path": "unmatched", "status": "200"})).To(Equal(2.0))
+		Expect(counterValue(registry, "http_server_requests_total", map[string]string{"method": "GET", "path": "unmatched", "status": "404"})).To(Equal(1.0))
+	})
+	It("records duration and in flight", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ok", nil))
+
+		families, err := registry.Gather()
+		Expect(err).To(BeNil())
+		names := []string{}
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		Expect(names).To(ContainElements("http_server_request_duration_seconds", "http_server_requests_in_flight"))
+	})
+	It("can be created twice with the same registry", func() {
+		Expect(func() {
+			libhttp.NewMetricsHandler(http.NotFoundHandler(), registry)
+		}).NotTo(Panic())
+	})
+	It("panics on conflicting metrics", func() {
+		conflictingRegistry := prometheus.NewRegistry()
+		conflictingRegistry.MustRegister(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_server_requests_total",
+			Help: "conflicting",
+		}, []string{"code"}))
+		Expect(func() {
+			libhttp.NewMetricsHandler(http.NotFoundHandler(), conflictingRegistry)
+		}).To(Panic())
+	})
+	It("uses the route template as path", func() {
+		router := mux.NewRouter()
+		router.Use(func(next http.Handler) http.Handler {
+			return libhttp.NewMetricsHandler(next, registry)
+		})
+		router.Path("/users/{id}").HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		Expect(counterValue(registry, "http_server_requests_total", map[string]string{"method": "GET", "path": "/users/{id}", "status": "200"})).To(Equal(1.0))
+	})
+	It("labels requests without matching route as unmatched", func() {
+		router := mux.NewRouter()
+		router.Use(func(next http.Handler) http.Handler {
+			return libhttp.NewMetricsHandler(next, registry)
+		})
+		router.Path("/users/{id}").HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {})
+		router.NotFoundHandler = libhttp.NewMetricsHandler(http.NotFoundHandler(), registry)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/abc123", nil))
+
+		Expect(counterValue(registry, "http_server_requests_total", map[string]string{"method": "GET", "path": "unmatched", "status": "404"})).To(Equal(1.0))
+	})
+})
+
+func counterValue(registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	families, err := registry.Gather()
+	Expect(err).To(BeNil())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if matchLabels(metric, labels) {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func matchLabels(metric *dto.Metric, labels map[string]string) bool {
+	if len(metric.GetLabel()) != len(labels) {
+		return false
+	}
+	for _, label := range metric.GetLabel() {
+		if labels[label.GetName()] != label.GetValue() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+// NewNegotiatingErrorHandler writes errors as JSON if the Accept header of the request prefers
+// application/json, otherwise as plain text like NewErrorHandler.
+func NewNegotiatingErrorHandler(handlerWithError WithError, optionFns ...func(*ErrorHandlerOptions)) http.Handler {
+	options := buildErrorHandlerOptions(optionFns...)
+	return newErrorHandler(handlerWithError, func(ctx context.Context, resp http.ResponseWriter, req *http.Request, err error) {
+		if acceptsJSON(req.Header.Get(AcceptHeaderName)) {
+			sendJSONErrorWithOptions(ctx, resp, req, err, options)
+			return
+		}
+		sendPlainTextError(ctx, resp, req, err, options)
+	})
+}
+
+// acceptsJSON returns true if application/json is acceptable and preferred over text/plain.
+// On a tie JSON only wins if it is listed explicitly.
+func acceptsJSON(accept string) bool {
+	acceptTypes := ParseAccept(accept)
+	jsonQuality, jsonSpecificity := acceptQuality(acceptTypes, ApplicationJsonContentType)
+	textQuality, _ := acceptQuality(acceptTypes, TextPlainContentType)
+	if jsonQuality <= 0 {
+		return false
+	}
+	return jsonQuality > textQuality || jsonQuality == textQuality && jsonSpecificity == 2
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NegotiatingErrorHandler", func() {
+	DescribeTable("content type",
+		func(accept string, expectedContentType string) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			if accept != "" {
+				req.Header.Set(libhttp.AcceptHeaderName, accept)
+			}
+			resp := httptest.NewRecorder()
+			libhttp.NewNegotiatingErrorHandler(libhttp.WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+				return libhttp.WrapWithCode(errors.New(ctx, "banana"), libhttp.ErrorCodeNotFound, http.StatusNotFound)
+			})).ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(HavePrefix(expectedContentType))
+		},
+		Entry("json", "application/json", "application/json"),
+		Entry("text", "text/plain", "text/plain"),
+		Entry("missing header", "", "text/plain"),
+		Entry("wildcard", "*/*", "text/plain"),
+		Entry("json with wildcard", "application/json, */*;q=0.1", "application/json"),
+		Entry("json lower quality", "application/json;q=0.5, text/plain", "text/plain"),
+		Entry("json rejected", "application/json;q=0, */*", "text/plain"),
+	)
+})
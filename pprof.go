@@ -5,7 +5,9 @@
 package http
 
 import (
+	"net/http"
 	"net/http/pprof"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -24,3 +26,22 @@ func RegisterPprof(router *mux.Router) {
 	router.PathPrefix("/debug/pprof/trace").HandlerFunc(pprof.Trace)
 	router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
 }
+
+// RegisterPprofHandlers registers the pprof endpoints below pathPrefix + "/debug/pprof/".
+// All endpoints are protected by a single NewDangerousHandlerWrapper,
+// so a passphrase from the log is required to access them.
+func RegisterPprofHandlers(router *mux.Router, pathPrefix string, optionFns ...func(*DangerousHandlerWrapperOptions)) {
+	pathPrefix = strings.TrimSuffix(pathPrefix, "/")
+	pprofRouter := http.NewServeMux()
+	pprofRouter.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	pprofRouter.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	pprofRouter.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	pprofRouter.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	pprofRouter.HandleFunc("/debug/pprof/", pprof.Index)
+	router.PathPrefix(pathPrefix + "/debug/pprof/").Handler(
+		NewDangerousHandlerWrapper(
+			http.StripPrefix(pathPrefix, pprofRouter),
+			optionFns...,
+		),
+	)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RegisterPprofHandlers", func() {
+	var router *mux.Router
+	var passphrase string
+	BeforeEach(func() {
+		passphrase = ""
+		router = mux.NewRouter()
+		libhttp.RegisterPprofHandlers(router, "/admin", libhttp.WithDangerousPassphraseSink(func(path string, pass string, expiresAt time.Time) {
+			passphrase = pass
+		}))
+	})
+	serve := func(target string, headerPassphrase string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if headerPassphrase != "" {
+			req.Header.Set(libhttp.DangerPassphraseHeaderName, headerPassphrase)
+		}
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	It("blocks without passphrase", func() {
+		resp := serve("/admin/debug/pprof/", "")
+		Expect(resp.Code).To(Equal(http.StatusForbidden))
+		Expect(passphrase).NotTo(BeEmpty())
+	})
+	It("serves the index with passphrase", func() {
+		serve("/admin/debug/pprof/", "")
+		resp := serve("/admin/debug/pprof/", passphrase)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(ContainSubstring("goroutine"))
+	})
+	It("serves named profiles with passphrase", func() {
+		serve("/admin/debug/pprof/heap", "")
+		resp := serve("/admin/debug/pprof/heap?debug=1", passphrase)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(ContainSubstring("heap profile"))
+	})
+})
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// NewGoroutineProfileHandler returns the stack traces of all goroutines as goroutine.pprof download.
+func NewGoroutineProfileHandler() WithError {
+	return newLookupProfileHandler("goroutine")
+}
+
+// NewBlockProfileHandler returns the block profile as block.pprof download.
+// The profile is empty unless enabled with runtime.SetBlockProfileRate.
+func NewBlockProfileHandler() WithError {
+	return newLookupProfileHandler("block")
+}
+
+// NewMutexProfileHandler returns the mutex contention profile as mutex.pprof download.
+// The profile is empty unless enabled with runtime.SetMutexProfileFraction.
+func NewMutexProfileHandler() WithError {
+	return newLookupProfileHandler("mutex")
+}
+
+func newLookupProfileHandler(name string) WithError {
+	return WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+		profile := pprof.Lookup(name)
+		if profile == nil {
+			return errors.Errorf(ctx, "profile %s not found", name)
+		}
+		resp.Header().Set(ContentTypeHeaderName, ApplicationOctetStreamContentType)
+		resp.Header().Set(ContentDispositionHeaderName, contentDispositionAttachment(name+".pprof"))
+		if err := profile.WriteTo(resp, 0); err != nil {
+			// headers are already sent, an error response would corrupt the download
+			glog.Warningf("write %s profile failed: %v", name, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProfileDownloadHandler", func() {
+	DescribeTable("returns the profile as download",
+		func(handler libhttp.WithError, fileName string) {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/debug/profile", nil)
+			libhttp.NewErrorHandler(handler).ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationOctetStreamContentType))
+			Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(Equal(`attachment; filename="` + fileName + `"`))
+			Expect(resp.Body.Len()).To(BeNumerically(">", 0))
+		},
+		Entry("goroutine", libhttp.NewGoroutineProfileHandler(), "goroutine.pprof"),
+		Entry("block", libhttp.NewBlockProfileHandler(), "block.pprof"),
+		Entry("mutex", libhttp.NewMutexProfileHandler(), "mutex.pprof"),
+	)
+	It("does not return an error if the client write fails", func() {
+		resp := &failingResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+		req := httptest.NewRequest(http.MethodGet, "/debug/profile", nil)
+		Expect(libhttp.NewGoroutineProfileHandler().ServeHTTP(context.Background(), resp, req)).To(BeNil())
+	})
+})
+
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (f *failingResponseWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
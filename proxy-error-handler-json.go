@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/golang/glog"
+)
+
+// NewJSONProxyErrorHandler responds upstream failures with a JSON 502 ErrorResponse
+// containing the target without credentials and query.
+// Nothing is written if the client canceled the request.
+func NewJSONProxyErrorHandler() ProxyErrorHandler {
+	return ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+		target := sanitizeTarget(req.URL)
+		if errors.Is(err, context.Canceled) || req.Context().Err() != nil {
+			glog.V(3).Infof("request to %s canceled by client: %v", target, err)
+			return
+		}
+		glog.V(1).Infof("request to %s failed: %v", target, err)
+		if err := SendJSONResponse(
+			req.Context(),
+			resp,
+			ErrorResponse{
+				Error: ErrorDetails{
+					Code:    ErrorCodeBadGateway,
+					Message: http.StatusText(http.StatusBadGateway),
+					Details: map[string]any{
+						"target": target,
+					},
+				},
+			},
+			http.StatusBadGateway,
+		); err != nil {
+			glog.Warningf("send error response failed: %v", err)
+		}
+	})
+}
+
+// sanitizeTarget returns scheme, host and path of the given url.
+func sanitizeTarget(target *url.URL) string {
+	if target == nil {
+		return ""
+	}
+	sanitized := url.URL{
+		Scheme: target.Scheme,
+		Host:   target.Host,
+		Path:   target.Path,
+	}
+	return sanitized.String()
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONProxyErrorHandler", func() {
+	var proxy http.Handler
+	var resp *httptest.ResponseRecorder
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		addr := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		target, err := url.Parse("http://user:secret@" + addr + "/api")
+		Expect(err).To(BeNil())
+		proxy = libhttp.NewProxy(http.DefaultTransport, target, libhttp.NewJSONProxyErrorHandler())
+		resp = httptest.NewRecorder()
+	})
+	It("returns json 502 on upstream connection error", func() {
+		proxy.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://external.example.com/users?token=abc", nil))
+		Expect(resp.Code).To(Equal(http.StatusBadGateway))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+
+		var errorResponse libhttp.ErrorResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+		Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeBadGateway))
+		Expect(errorResponse.Error.Details).To(HaveKey("target"))
+		Expect(errorResponse.Error.Details["target"]).To(HavePrefix("http://127.0.0.1:"))
+		Expect(errorResponse.Error.Details["target"]).To(HaveSuffix("/api/users"))
+		Expect(errorResponse.Error.Details["target"]).NotTo(ContainSubstring("secret"))
+		Expect(errorResponse.Error.Details["target"]).NotTo(ContainSubstring("token"))
+	})
+	It("writes nothing if the client canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		proxy.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://external.example.com/users", nil).WithContext(ctx))
+		Expect(resp.Body.Len()).To(Equal(0))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(BeEmpty())
+	})
+})
@@ -8,6 +8,8 @@ import (
 	"context"
 	"errors"
 	"io"
+	"math"
+	"math/rand/v2"
 	"net/http"
 
 	libsentry "github.com/bborbe/sentry"
@@ -15,10 +17,67 @@ import (
 	"github.com/golang/glog"
 )
 
-func NewSentryProxyErrorHandler(sentryClient libsentry.Client) ProxyErrorHandler {
+//counterfeiter:generate -o mocks/sentry-client.go --fake-name SentryClient github.com/bborbe/sentry.Client
+
+// SentryOptions configures which errors are reported to Sentry.
+type SentryOptions struct {
+	// IgnoreErrors are not reported in addition to the errors of IsIgnoredSentryError
+	IgnoreErrors []error
+	// SampleRate is the fraction of errors reported between 0.0 and 1.0
+	SampleRate float64
+}
+
+// DefaultSentryOptions reports all errors not ignored by IsIgnoredSentryError.
+func DefaultSentryOptions() SentryOptions {
+	return SentryOptions{
+		SampleRate: 1,
+	}
+}
+
+// WithSentryIgnoreErrors adds errors that are not reported, matched with errors.Is.
+func WithSentryIgnoreErrors(errs ...error) func(*SentryOptions) {
+	return func(options *SentryOptions) {
+		options.IgnoreErrors = append(options.IgnoreErrors, errs...)
+	}
+}
+
+// WithSentrySampleRate reports only the given fraction of errors, e.g. to avoid flooding Sentry during an outage.
+// The rate is clamped to 0.0-1.0.
+func WithSentrySampleRate(sampleRate float64) func(*SentryOptions) {
+	return func(options *SentryOptions) {
+		options.SampleRate = math.Max(0, math.Min(1, sampleRate))
+	}
+}
+
+func buildSentryOptions(optionFns ...func(*SentryOptions)) SentryOptions {
+	options := DefaultSentryOptions()
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return options
+}
+
+// shouldReport returns true if the error is not ignored and sampled.
+func (s SentryOptions) shouldReport(err error) bool {
+	if IsIgnoredSentryError(err) {
+		return false
+	}
+	for _, ignoreError := range s.IgnoreErrors {
+		if errors.Is(err, ignoreError) {
+			return false
+		}
+	}
+	if s.SampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < s.SampleRate // #nosec G404 sampling needs no secure random
+}
+
+func NewSentryProxyErrorHandler(sentryClient libsentry.Client, optionFns ...func(*SentryOptions)) ProxyErrorHandler {
+	options := buildSentryOptions(optionFns...)
 	return ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
 		glog.V(1).Infof("handle request to %s for %s failed: %v", req.URL.String(), req.Header.Get("user-agent"), err)
-		if IsIgnoredSentryError(err) == false {
+		if options.shouldReport(err) {
 			sentryClient.CaptureException(
 				err,
 				&sentry.EventHint{
@@ -47,7 +106,7 @@ func IsIgnoredSentryError(err error) bool {
 		return true
 	}
 	for _, ignoredError := range sentryIgnoreErrors {
-		if errors.Is(ignoredError, context.Canceled) {
+		if errors.Is(err, ignoredError) {
 			return true
 		}
 	}
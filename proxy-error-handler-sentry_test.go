@@ -5,7 +5,16 @@
 package http_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+
 	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -18,4 +27,51 @@ var _ = Describe("SentryProxyErrorHandler", func() {
 	It("returns handler", func() {
 		Expect(handler).NotTo(BeNil())
 	})
+	Context("with client", func() {
+		var sentryClient *mocks.SentryClient
+		BeforeEach(func() {
+			sentryClient = &mocks.SentryClient{}
+		})
+		handleErrors := func(counter int, err error, optionFns ...func(*libhttp.SentryOptions)) {
+			handler = libhttp.NewSentryProxyErrorHandler(sentryClient, optionFns...)
+			for i := 0; i < counter; i++ {
+				handler.HandleError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), err)
+			}
+		}
+		It("captures errors", func() {
+			handleErrors(1, errors.New("banana"))
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+		})
+		It("does not capture custom ignored errors", func() {
+			handleErrors(1, fmt.Errorf("read failed: %w", syscall.ECONNRESET), libhttp.WithSentryIgnoreErrors(syscall.ECONNRESET))
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+		It("captures nothing with sample rate 0", func() {
+			handleErrors(100, errors.New("banana"), libhttp.WithSentrySampleRate(0))
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+		It("captures everything with sample rate 1", func() {
+			handleErrors(100, errors.New("banana"), libhttp.WithSentrySampleRate(1))
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(100))
+		})
+		It("returns bad gateway", func() {
+			resp := httptest.NewRecorder()
+			libhttp.NewSentryProxyErrorHandler(sentryClient).HandleError(resp, httptest.NewRequest(http.MethodGet, "/", nil), errors.New("banana"))
+			Expect(resp.Code).To(Equal(http.StatusBadGateway))
+		})
+	})
 })
+
+var _ = DescribeTable("IsIgnoredSentryError",
+	func(err error, expected bool) {
+		Expect(libhttp.IsIgnoredSentryError(err)).To(Equal(expected))
+	},
+	Entry("context canceled", context.Canceled, true),
+	Entry("wrapped context canceled", fmt.Errorf("request failed: %w", context.Canceled), true),
+	Entry("deadline exceeded", context.DeadlineExceeded, true),
+	Entry("wrapped deadline exceeded", fmt.Errorf("request failed: %w", context.DeadlineExceeded), true),
+	Entry("eof", io.EOF, true),
+	Entry("wrapped eof", fmt.Errorf("read failed: %w", io.EOF), true),
+	Entry("other error", errors.New("banana"), false),
+	Entry("unexpected eof", io.ErrUnexpectedEOF, false),
+)
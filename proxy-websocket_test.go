@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/websocket"
+)
+
+var _ = Describe("Proxy websocket", func() {
+	var backend *httptest.Server
+	var proxyServer *httptest.Server
+	BeforeEach(func() {
+		backend = httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+			_, _ = io.Copy(conn, conn)
+		}))
+		backendURL, err := url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+		proxyServer = httptest.NewServer(libhttp.NewProxy(
+			libhttp.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+			backendURL,
+			libhttp.ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+				resp.WriteHeader(http.StatusBadGateway)
+			}),
+		))
+	})
+	AfterEach(func() {
+		proxyServer.Close()
+		backend.Close()
+	})
+	It("round-trips a message through the proxy", func() {
+		conn, err := websocket.Dial(strings.Replace(proxyServer.URL, "http://", "ws://", 1), "", proxyServer.URL)
+		Expect(err).To(BeNil())
+		defer conn.Close()
+
+		Expect(websocket.Message.Send(conn, "hello")).To(Succeed())
+		var message string
+		Expect(websocket.Message.Receive(conn, &message)).To(Succeed())
+		Expect(message).To(Equal("hello"))
+	})
+})
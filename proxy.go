@@ -5,21 +5,208 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
+	"time"
 )
 
+// ProxyOptions configures the reverse proxy created by NewProxy.
+type ProxyOptions struct {
+	// RewriteLocation replaces the target scheme and host in Location headers of redirects
+	// with the scheme and host of the incoming request
+	RewriteLocation bool
+	// FlushInterval flushes the response to the client periodically, e.g. for long polling.
+	// Zero disables periodic flushing, a negative value flushes after each write.
+	// text/event-stream responses are always flushed immediately.
+	FlushInterval time.Duration
+	// ForwardedHeaders sets X-Forwarded-Host and X-Forwarded-Proto of the incoming request.
+	// X-Forwarded-For is always appended.
+	ForwardedHeaders bool
+	// ModifyRequest is called with the outgoing request before it is sent to the target,
+	// an error is passed to the ProxyErrorHandler
+	ModifyRequest func(req *http.Request) error
+	// ModifyResponse is called with the response of the target like httputil.ReverseProxy.ModifyResponse,
+	// an error is passed to the ProxyErrorHandler
+	ModifyResponse func(resp *http.Response) error
+	// RetryLimit is the number of retries of requests with idempotent methods, zero disables retries
+	RetryLimit int
+	// RetryDelay is the delay between two attempts
+	RetryDelay time.Duration
+}
+
+// WithProxyRewriteLocation rewrites redirects to the target host back to the host of the incoming request.
+func WithProxyRewriteLocation() func(*ProxyOptions) {
+	return func(options *ProxyOptions) {
+		options.RewriteLocation = true
+	}
+}
+
+// WithProxyFlushInterval flushes the proxied response to the client at the given interval.
+func WithProxyFlushInterval(flushInterval time.Duration) func(*ProxyOptions) {
+	return func(options *ProxyOptions) {
+		options.FlushInterval = flushInterval
+	}
+}
+
+// WithProxyForwardedHeaders sets X-Forwarded-Host and X-Forwarded-Proto on the proxied request.
+func WithProxyForwardedHeaders() func(*ProxyOptions) {
+	return func(options *ProxyOptions) {
+		options.ForwardedHeaders = true
+	}
+}
+
+// WithProxyModifyRequest modifies the outgoing request, e.g. to rewrite the body.
+// Set ContentLength and the Content-Length header if the length of the body changes.
+func WithProxyModifyRequest(modifyRequest func(req *http.Request) error) func(*ProxyOptions) {
+	return func(options *ProxyOptions) {
+		options.ModifyRequest = modifyRequest
+	}
+}
+
+// WithProxyModifyResponse modifies the response before it is copied to the client, e.g. to rewrite the body.
+// Set ContentLength and the Content-Length header if the length of the body changes.
+func WithProxyModifyResponse(modifyResponse func(resp *http.Response) error) func(*ProxyOptions) {
+	return func(options *ProxyOptions) {
+		options.ModifyResponse = modifyResponse
+	}
+}
+
+// WithProxyRetry retries requests with idempotent methods like GET, PUT or DELETE on transient errors
+// and 502, 503 or 504 responses, other responses are returned without retry.
+// The request body is buffered for retries up to DefaultRoundTripperRetryMaxBodyBytes, requests with a larger body
+// and requests with other methods like POST are sent once.
+func WithProxyRetry(retryLimit int, retryDelay time.Duration) func(*ProxyOptions) {
+	return func(options *ProxyOptions) {
+		options.RetryLimit = retryLimit
+		options.RetryDelay = retryDelay
+	}
+}
+
+// proxyRetryStatusCodes are the status codes of transient failures retried by WithProxyRetry.
+var proxyRetryStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type proxyContextKey string
+
+const externalURLContextKey proxyContextKey = "external-url"
+
+// NewProxy forwards requests to apiUrl using the given transport.
+// Upgrade requests like WebSockets are tunneled by httputil.ReverseProxy,
+// which requires the transport to return the writable body of the 101 response as *http.Transport does.
+// Requests are sent once unless WithProxyRetry is set, a retrying transport must replay the request body itself.
 func NewProxy(
 	transport http.RoundTripper,
 	apiUrl *url.URL,
 	proxyErrorHandler ProxyErrorHandler,
+	optionFns ...func(*ProxyOptions),
 ) http.Handler {
+	var options ProxyOptions
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	retryTransport := transport
+	if options.RetryLimit > 0 {
+		retryTransport = &retryRoundTripper{
+			roundTripper:     transport,
+			retryLimit:       options.RetryLimit,
+			retryDelay:       options.RetryDelay,
+			retryStatusCodes: proxyRetryStatusCodes,
+			maxBodyBytes:     DefaultRoundTripperRetryMaxBodyBytes,
+		}
+	}
+	// hop-by-hop headers (RFC 7230) of request and response are removed by httputil.ReverseProxy
 	reverseProxy := httputil.NewSingleHostReverseProxy(apiUrl)
 	reverseProxy.ErrorHandler = proxyErrorHandler.HandleError
+	reverseProxy.FlushInterval = options.FlushInterval
 	reverseProxy.Transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		req.Host = apiUrl.Host
+		if options.ModifyRequest != nil {
+			if err := options.ModifyRequest(req); err != nil {
+				return nil, err
+			}
+		}
+		if isIdempotentMethod(req.Method) {
+			return retryTransport.RoundTrip(req)
+		}
 		return transport.RoundTrip(req)
 	})
+	director := reverseProxy.Director
+	reverseProxy.Director = func(req *http.Request) {
+		externalURL := &url.URL{
+			Scheme: requestScheme(req),
+			Host:   req.Host,
+		}
+		director(req)
+		if options.ForwardedHeaders {
+			req.Header.Set("X-Forwarded-Host", externalURL.Host)
+			req.Header.Set("X-Forwarded-Proto", externalURL.Scheme)
+		}
+		if options.RewriteLocation {
+			*req = *req.WithContext(context.WithValue(req.Context(), externalURLContextKey, externalURL))
+		}
+	}
+	if options.RewriteLocation || options.ModifyResponse != nil {
+		reverseProxy.ModifyResponse = func(resp *http.Response) error {
+			if options.RewriteLocation {
+				rewriteLocation(resp, apiUrl)
+			}
+			if options.ModifyResponse != nil {
+				return options.ModifyResponse(resp)
+			}
+			return nil
+		}
+	}
 	return reverseProxy
 }
+
+// NewProxyFromURL forwards requests to target with the default transport of CreateRoundTripper
+// and NewJSONProxyErrorHandler. The transport does not retry, use WithProxyRetry to retry idempotent requests.
+func NewProxyFromURL(target *url.URL, optionFns ...func(*ProxyOptions)) http.Handler {
+	return NewProxy(
+		CreateRoundTripper(WithRoundTripperRetry(0, 0)),
+		target,
+		NewJSONProxyErrorHandler(),
+		optionFns...,
+	)
+}
+
+// rewriteLocation replaces the target scheme and host of a redirect Location with the external ones.
+func rewriteLocation(resp *http.Response, apiUrl *url.URL) {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return
+	}
+	externalURL, ok := resp.Request.Context().Value(externalURLContextKey).(*url.URL)
+	if !ok {
+		return
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || !strings.EqualFold(location.Host, apiUrl.Host) {
+		return
+	}
+	location.Scheme = externalURL.Scheme
+	location.Host = externalURL.Host
+	resp.Header.Set("Location", location.String())
+}
+
+// isIdempotentMethod returns true for methods that can be retried without side effects according to RFC 9110.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func requestScheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
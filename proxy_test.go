@@ -5,12 +5,19 @@
 package http_test
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	libhttp "github.com/bborbe/http"
 	"github.com/bborbe/http/mocks"
@@ -82,3 +89,361 @@ var _ = Describe("Proxy", func() {
 		})
 	})
 })
+
+var _ = Describe("Proxy rewrite location", func() {
+	var backend *httptest.Server
+	var backendURL *url.URL
+	BeforeEach(func() {
+		backend = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			http.Redirect(resp, req, "http://"+req.Host+"/login?next=%2Fhome", http.StatusFound)
+		}))
+		var err error
+		backendURL, err = url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		backend.Close()
+	})
+	serve := func(optionFns ...func(*libhttp.ProxyOptions)) *httptest.ResponseRecorder {
+		proxy := libhttp.NewProxy(
+			http.DefaultTransport,
+			backendURL,
+			libhttp.ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+				Fail(err.Error())
+			}),
+			optionFns...,
+		)
+		resp := httptest.NewRecorder()
+		proxy.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://external.example.com/home", nil))
+		return resp
+	}
+	It("rewrites the internal location to the external host", func() {
+		resp := serve(libhttp.WithProxyRewriteLocation())
+		Expect(resp.Code).To(Equal(http.StatusFound))
+		Expect(resp.Header().Get("Location")).To(Equal("http://external.example.com/login?next=%2Fhome"))
+	})
+	It("keeps the location by default", func() {
+		resp := serve()
+		Expect(resp.Code).To(Equal(http.StatusFound))
+		Expect(resp.Header().Get("Location")).To(Equal(backend.URL + "/login?next=%2Fhome"))
+	})
+})
+
+var _ = Describe("Proxy modify hooks", func() {
+	var backend *httptest.Server
+	var backendURL *url.URL
+	var backendBody string
+	var handledErr error
+	BeforeEach(func() {
+		backendBody = ""
+		handledErr = nil
+		backend = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			backendBody = string(body)
+			resp.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(resp, `{"name":"alice","internal":"secret"}`)
+		}))
+		var err error
+		backendURL, err = url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		backend.Close()
+	})
+	serve := func(optionFns ...func(*libhttp.ProxyOptions)) *httptest.ResponseRecorder {
+		proxy := libhttp.NewProxy(
+			http.DefaultTransport,
+			backendURL,
+			libhttp.ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+				handledErr = err
+				resp.WriteHeader(http.StatusBadGateway)
+			}),
+			optionFns...,
+		)
+		resp := httptest.NewRecorder()
+		proxy.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "http://external.example.com/users", bytes.NewBufferString(`{"name":"alice"}`)))
+		return resp
+	}
+	replaceBody := func(body []byte) (io.ReadCloser, int64, string) {
+		return io.NopCloser(bytes.NewReader(body)), int64(len(body)), fmt.Sprint(len(body))
+	}
+	It("modifies the request body", func() {
+		resp := serve(libhttp.WithProxyModifyRequest(func(req *http.Request) error {
+			var contentLength string
+			req.Body, req.ContentLength, contentLength = replaceBody([]byte(`{"name":"alice","tenant":"acme"}`))
+			req.Header.Set("Content-Length", contentLength)
+			return nil
+		}))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(backendBody).To(Equal(`{"name":"alice","tenant":"acme"}`))
+	})
+	It("modifies the response body", func() {
+		resp := serve(libhttp.WithProxyModifyResponse(func(resp *http.Response) error {
+			var data map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+				return err
+			}
+			_ = resp.Body.Close()
+			delete(data, "internal")
+			body, err := json.Marshal(data)
+			if err != nil {
+				return err
+			}
+			var contentLength string
+			resp.Body, resp.ContentLength, contentLength = replaceBody(body)
+			resp.Header.Set("Content-Length", contentLength)
+			return nil
+		}))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal(`{"name":"alice"}`))
+		Expect(resp.Header().Get("Content-Length")).To(Equal("16"))
+	})
+	It("passes a modify request error to the error handler", func() {
+		resp := serve(libhttp.WithProxyModifyRequest(func(req *http.Request) error {
+			return errors.New("banana")
+		}))
+		Expect(resp.Code).To(Equal(http.StatusBadGateway))
+		Expect(handledErr).To(MatchError("banana"))
+		Expect(backendBody).To(BeEmpty())
+	})
+	It("passes a modify response error to the error handler", func() {
+		resp := serve(libhttp.WithProxyModifyResponse(func(resp *http.Response) error {
+			return errors.New("banana")
+		}))
+		Expect(resp.Code).To(Equal(http.StatusBadGateway))
+		Expect(handledErr).To(MatchError("banana"))
+	})
+})
+
+var _ = Describe("Proxy retry", func() {
+	var backend *httptest.Server
+	var backendURL *url.URL
+	var counter int32
+	var failures int32
+	var failureStatusCode int32
+	var bodies []string
+	BeforeEach(func() {
+		counter = 0
+		failures = 1
+		atomic.StoreInt32(&failureStatusCode, http.StatusServiceUnavailable)
+		bodies = nil
+	})
+	JustBeforeEach(func() {
+		failures := failures
+		backend = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			if atomic.AddInt32(&counter, 1) <= failures {
+				resp.WriteHeader(int(atomic.LoadInt32(&failureStatusCode)))
+				return
+			}
+			fmt.Fprint(resp, "ok")
+		}))
+		var err error
+		backendURL, err = url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		backend.Close()
+	})
+	serve := func(method string, body string) *httptest.ResponseRecorder {
+		proxy := libhttp.NewProxy(
+			http.DefaultTransport,
+			backendURL,
+			libhttp.ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+				Fail(err.Error())
+			}),
+			libhttp.WithProxyRetry(2, 0),
+		)
+		resp := httptest.NewRecorder()
+		proxy.ServeHTTP(resp, httptest.NewRequest(method, "http://external.example.com/users", bytes.NewBufferString(body)))
+		return resp
+	}
+	It("retries a GET on 503", func() {
+		resp := serve(http.MethodGet, "")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("ok"))
+		Expect(atomic.LoadInt32(&counter)).To(Equal(int32(2)))
+	})
+	It("retries a PUT with the request body", func() {
+		resp := serve(http.MethodPut, "banana")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(bodies).To(Equal([]string{"banana", "banana"}))
+	})
+	It("does not retry a POST", func() {
+		resp := serve(http.MethodPost, "banana")
+		Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(atomic.LoadInt32(&counter)).To(Equal(int32(1)))
+	})
+	It("sends a PUT with a body larger than the buffer once", func() {
+		body := strings.Repeat("a", libhttp.DefaultRoundTripperRetryMaxBodyBytes+1)
+		resp := serve(http.MethodPut, body)
+		Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(bodies).To(Equal([]string{body}))
+	})
+	DescribeTable("does not retry other error responses",
+		func(statusCode int) {
+			atomic.StoreInt32(&failureStatusCode, int32(statusCode))
+			resp := serve(http.MethodGet, "")
+			Expect(resp.Code).To(Equal(statusCode))
+			Expect(atomic.LoadInt32(&counter)).To(Equal(int32(1)))
+		},
+		Entry("500", http.StatusInternalServerError),
+		Entry("409", http.StatusConflict),
+		Entry("429", http.StatusTooManyRequests),
+	)
+	Context("backend always returns 503", func() {
+		BeforeEach(func() {
+			failures = math.MaxInt32
+		})
+		It("stops after the retry limit", func() {
+			resp := serve(http.MethodGet, "")
+			Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(atomic.LoadInt32(&counter)).To(Equal(int32(3)))
+		})
+	})
+})
+
+var _ = Describe("NewProxyFromURL", func() {
+	var backend *httptest.Server
+	var backendURL *url.URL
+	BeforeEach(func() {
+		backend = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(resp, "hello %s", req.URL.Path)
+		}))
+		var err error
+		backendURL, err = url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		backend.Close()
+	})
+	It("proxies to the backend", func() {
+		resp := httptest.NewRecorder()
+		libhttp.NewProxyFromURL(backendURL).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://external.example.com/users", nil))
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("hello /users"))
+	})
+	It("returns a JSON 502 if the backend is down", func() {
+		backend.Close()
+		resp := httptest.NewRecorder()
+		libhttp.NewProxyFromURL(backendURL).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://external.example.com/users", nil))
+		Expect(resp.Code).To(Equal(http.StatusBadGateway))
+		var errorResponse libhttp.ErrorResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+		Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeBadGateway))
+	})
+})
+
+var _ = Describe("Proxy flush interval", func() {
+	var backend *httptest.Server
+	var proxyServer *httptest.Server
+	var secondChunk chan struct{}
+	BeforeEach(func() {
+		secondChunk = make(chan struct{})
+		backend = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(resp, "first\n")
+			resp.(http.Flusher).Flush()
+			select {
+			case <-secondChunk:
+			case <-time.After(5 * time.Second):
+			}
+			fmt.Fprint(resp, "second\n")
+		}))
+		backendURL, err := url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+		proxyServer = httptest.NewServer(libhttp.NewProxy(
+			http.DefaultTransport,
+			backendURL,
+			libhttp.ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+				resp.WriteHeader(http.StatusBadGateway)
+			}),
+			libhttp.WithProxyFlushInterval(10*time.Millisecond),
+		))
+	})
+	AfterEach(func() {
+		proxyServer.Close()
+		backend.Close()
+	})
+	It("streams chunks before the backend response is complete", func() {
+		resp, err := http.Get(proxyServer.URL)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		reader := bufio.NewReader(resp.Body)
+
+		line, err := reader.ReadString('\n')
+		Expect(err).To(BeNil())
+		Expect(line).To(Equal("first\n"))
+
+		close(secondChunk)
+		line, err = reader.ReadString('\n')
+		Expect(err).To(BeNil())
+		Expect(line).To(Equal("second\n"))
+	})
+})
+
+var _ = Describe("Proxy headers", func() {
+	var backend *httptest.Server
+	var backendURL *url.URL
+	var backendHeader http.Header
+	BeforeEach(func() {
+		backend = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			backendHeader = req.Header.Clone()
+			resp.Header().Set("Connection", "X-Backend-Hop")
+			resp.Header().Set("X-Backend-Hop", "secret")
+			resp.Header().Set("Keep-Alive", "timeout=5")
+			resp.Header().Set("X-Backend", "value")
+		}))
+		var err error
+		backendURL, err = url.Parse(backend.URL)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		backend.Close()
+	})
+	serve := func(optionFns ...func(*libhttp.ProxyOptions)) *httptest.ResponseRecorder {
+		proxy := libhttp.NewProxy(
+			http.DefaultTransport,
+			backendURL,
+			libhttp.ProxyErrorHandlerFunc(func(resp http.ResponseWriter, req *http.Request, err error) {
+				Fail(err.Error())
+			}),
+			optionFns...,
+		)
+		req := httptest.NewRequest(http.MethodGet, "http://external.example.com/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("Connection", "X-Client-Hop")
+		req.Header.Set("X-Client-Hop", "secret")
+		req.Header.Set("Keep-Alive", "timeout=5")
+		req.Header.Set("Proxy-Authorization", "Basic Zm9vOmJhcg==")
+		req.Header.Set("X-Forwarded-For", "192.168.0.1")
+		req.Header.Set("X-Client", "value")
+		resp := httptest.NewRecorder()
+		proxy.ServeHTTP(resp, req)
+		return resp
+	}
+	It("removes hop-by-hop request headers", func() {
+		serve()
+		Expect(backendHeader.Get("X-Client-Hop")).To(BeEmpty())
+		Expect(backendHeader.Get("Keep-Alive")).To(BeEmpty())
+		Expect(backendHeader.Get("Proxy-Authorization")).To(BeEmpty())
+		Expect(backendHeader.Get("X-Client")).To(Equal("value"))
+	})
+	It("removes hop-by-hop response headers", func() {
+		resp := serve()
+		Expect(resp.Header().Get("Connection")).To(BeEmpty())
+		Expect(resp.Header().Get("X-Backend-Hop")).To(BeEmpty())
+		Expect(resp.Header().Get("Keep-Alive")).To(BeEmpty())
+		Expect(resp.Header().Get("X-Backend")).To(Equal("value"))
+	})
+	It("appends X-Forwarded-For", func() {
+		serve()
+		Expect(backendHeader.Get("X-Forwarded-For")).To(Equal("192.168.0.1, 10.0.0.1"))
+		Expect(backendHeader.Get("X-Forwarded-Host")).To(BeEmpty())
+	})
+	It("sets forwarded headers", func() {
+		serve(libhttp.WithProxyForwardedHeaders())
+		Expect(backendHeader.Get("X-Forwarded-Host")).To(Equal("external.example.com"))
+		Expect(backendHeader.Get("X-Forwarded-Proto")).To(Equal("http"))
+	})
+})
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// QueryParams returns a parser for the query parameters of the request.
+// All failures are returned as errors with ErrorCodeValidation, status 400 and the message per field
+// in the details under "fields" like ValidationError, so they flow through NewJSONErrorHandler.
+func QueryParams(req *http.Request) *QueryParamsParser {
+	return &QueryParamsParser{
+		ctx:    req.Context(),
+		values: req.URL.Query(),
+	}
+}
+
+// QueryParamsParser reads typed query parameters, see QueryParams.
+type QueryParamsParser struct {
+	ctx    context.Context
+	values url.Values
+}
+
+// RequireString returns the value of the parameter or an error if it is missing or empty.
+func (q *QueryParamsParser) RequireString(name string) (string, error) {
+	value := q.values.Get(name)
+	if value == "" {
+		return "", q.invalid(name, "is required", nil)
+	}
+	return value, nil
+}
+
+// OptionalString returns the value of the parameter or defaultValue if it is missing or empty.
+func (q *QueryParamsParser) OptionalString(name string, defaultValue string) string {
+	if value := q.values.Get(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// RequireInt returns the value of the parameter as int or an error if it is missing or not an integer.
+func (q *QueryParamsParser) RequireInt(name string) (int, error) {
+	value, err := q.RequireString(name)
+	if err != nil {
+		return 0, err
+	}
+	return q.parseInt(name, value)
+}
+
+// OptionalInt returns the value of the parameter as int, defaultValue if it is missing,
+// or an error if it is not an integer.
+func (q *QueryParamsParser) OptionalInt(name string, defaultValue int) (int, error) {
+	value := q.values.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return q.parseInt(name, value)
+}
+
+// RequireEnum returns the value of the parameter or an error with the allowed values in the details
+// if it is missing or not one of allowed.
+func (q *QueryParamsParser) RequireEnum(name string, allowed ...string) (string, error) {
+	value, err := q.RequireString(name)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(allowed, value) {
+		return "", q.invalid(name, "must be one of "+strings.Join(allowed, ", "), map[string]any{"allowed": allowed})
+	}
+	return value, nil
+}
+
+func (q *QueryParamsParser) parseInt(name string, value string) (int, error) {
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, q.invalid(name, "must be an integer", nil)
+	}
+	return result, nil
+}
+
+// invalid returns the validation error of the parameter built like ValidationError.Err.
+func (q *QueryParamsParser) invalid(name string, message string, details map[string]any) error {
+	var validationError ValidationError
+	validationError.Add(name, message)
+	return validationError.errWithDetails(details)
+}
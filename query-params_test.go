@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QueryParams", func() {
+	var queryParams *libhttp.QueryParamsParser
+	BeforeEach(func() {
+		queryParams = libhttp.QueryParams(httptest.NewRequest(http.MethodGet, "http://example.com/users?limit=10&sort=name&offset=abc", nil))
+	})
+	It("returns valid values", func() {
+		limit, err := queryParams.RequireInt("limit")
+		Expect(err).To(BeNil())
+		Expect(limit).To(Equal(10))
+
+		sort, err := queryParams.RequireEnum("sort", "name", "created")
+		Expect(err).To(BeNil())
+		Expect(sort).To(Equal("name"))
+
+		page, err := queryParams.OptionalInt("page", 1)
+		Expect(err).To(BeNil())
+		Expect(page).To(Equal(1))
+
+		Expect(queryParams.OptionalString("filter", "all")).To(Equal("all"))
+		Expect(queryParams.OptionalString("sort", "created")).To(Equal("name"))
+	})
+	It("returns an error for a missing required int", func() {
+		_, err := queryParams.RequireInt("page")
+		Expect(err).NotTo(BeNil())
+		code, statusCode, details := libhttp.ResolveError(err)
+		Expect(code).To(Equal(libhttp.ErrorCodeValidation))
+		Expect(statusCode).To(Equal(http.StatusBadRequest))
+		Expect(details).To(Equal(map[string]any{"fields": map[string]any{"page": "is required"}}))
+	})
+	It("returns an error for an invalid int", func() {
+		_, err := queryParams.OptionalInt("offset", 0)
+		Expect(err).NotTo(BeNil())
+		_, _, details := libhttp.ResolveError(err)
+		Expect(details).To(Equal(map[string]any{"fields": map[string]any{"offset": "must be an integer"}}))
+	})
+	It("returns an error with the allowed values for a value not in the enum", func() {
+		_, err := queryParams.RequireEnum("sort", "created", "updated")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(Equal("validation failed: sort: must be one of created, updated"))
+		code, statusCode, details := libhttp.ResolveError(err)
+		Expect(code).To(Equal(libhttp.ErrorCodeValidation))
+		Expect(statusCode).To(Equal(http.StatusBadRequest))
+		Expect(details).To(HaveKeyWithValue("allowed", []string{"created", "updated"}))
+		Expect(details).To(HaveKeyWithValue("fields", map[string]any{"sort": "must be one of created, updated"}))
+	})
+	It("returns is required for a missing enum", func() {
+		_, err := queryParams.RequireEnum("order", "asc", "desc")
+		Expect(err).NotTo(BeNil())
+		_, _, details := libhttp.ResolveError(err)
+		Expect(details).To(Equal(map[string]any{"fields": map[string]any{"order": "is required"}}))
+	})
+	It("does not mix up a parameter named allowed with the allowed values", func() {
+		_, err := libhttp.QueryParams(httptest.NewRequest(http.MethodGet, "http://example.com/users?allowed=maybe", nil)).RequireEnum("allowed", "yes", "no")
+		Expect(err).NotTo(BeNil())
+		_, _, details := libhttp.ResolveError(err)
+		Expect(details).To(HaveKeyWithValue("allowed", []string{"yes", "no"}))
+		Expect(details).To(HaveKeyWithValue("fields", map[string]any{"allowed": "must be one of yes, no"}))
+	})
+})
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+	"golang.org/x/time/rate"
+)
+
+// DefaultRateLimitIdleTimeout is the time after which the limiter of an inactive key is removed.
+const DefaultRateLimitIdleTimeout = 10 * time.Minute
+
+// RateLimitOptions configures NewRateLimitHandler.
+type RateLimitOptions struct {
+	// IdleTimeout removes limiters of keys without requests for this duration
+	IdleTimeout time.Duration
+}
+
+// WithRateLimitIdleTimeout sets the time after which the limiter of an inactive key is removed.
+func WithRateLimitIdleTimeout(idleTimeout time.Duration) func(*RateLimitOptions) {
+	return func(options *RateLimitOptions) {
+		options.IdleTimeout = idleTimeout
+	}
+}
+
+// NewRateLimitHandler limits requests per key, e.g. the client IP or API key returned by keyFunc.
+// The limiter of a key is created with limiter on its first request.
+// Requests exceeding the limit get 429 with Retry-After and an ErrorCodeRateLimited JSON body.
+func NewRateLimitHandler(
+	next http.Handler,
+	keyFunc func(*http.Request) string,
+	limiter func(key string) *rate.Limiter,
+	optionFns ...func(*RateLimitOptions),
+) http.Handler {
+	options := RateLimitOptions{
+		IdleTimeout: DefaultRateLimitIdleTimeout,
+	}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	limiters := &rateLimiters{
+		newLimiter:  limiter,
+		idleTimeout: options.IdleTimeout,
+		entries:     make(map[string]*rateLimiterEntry),
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		key := keyFunc(req)
+		if delay, ok := limiters.Allow(key); !ok {
+			ctx := req.Context()
+			glog.V(2).Infof("%s request to %s for key %s rate limited", req.Method, req.URL.Path, key)
+			resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			sendJSONError(ctx, resp, req, WrapWithRateLimited(errors.Errorf(ctx, "rate limit exceeded")))
+			return
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+type rateLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiters holds a limiter per key and removes limiters of idle keys.
+type rateLimiters struct {
+	newLimiter  func(key string) *rate.Limiter
+	idleTimeout time.Duration
+
+	mux       sync.Mutex
+	entries   map[string]*rateLimiterEntry
+	lastSweep time.Time
+}
+
+// Allow consumes a token of the key and returns false with the delay until the next token if none is left.
+func (r *rateLimiters) Allow(key string) (time.Duration, bool) {
+	now := time.Now()
+	r.mux.Lock()
+	r.sweep(now)
+	entry, ok := r.entries[key]
+	if !ok {
+		entry = &rateLimiterEntry{limiter: r.newLimiter(key)}
+		r.entries[key] = entry
+	}
+	entry.lastSeen = now
+	r.mux.Unlock()
+
+	if entry.limiter.AllowN(now, 1) {
+		return 0, true
+	}
+	reservation := entry.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return time.Second, false
+	}
+	delay := reservation.DelayFrom(now)
+	reservation.CancelAt(now)
+	if delay < time.Second {
+		delay = time.Second
+	}
+	return delay, false
+}
+
+// sweep removes idle limiters at most once per idle timeout.
+func (r *rateLimiters) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.idleTimeout {
+		return
+	}
+	r.lastSweep = now
+	for key, entry := range r.entries {
+		if now.Sub(entry.lastSeen) >= r.idleTimeout {
+			delete(r.entries, key)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/time/rate"
+)
+
+var _ = Describe("RateLimitHandler", func() {
+	var handler http.Handler
+	var created int32
+	BeforeEach(func() {
+		created = 0
+		handler = libhttp.NewRateLimitHandler(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}),
+			func(req *http.Request) string {
+				return req.Header.Get("X-Api-Key")
+			},
+			func(key string) *rate.Limiter {
+				atomic.AddInt32(&created, 1)
+				return rate.NewLimiter(rate.Every(time.Minute), 3)
+			},
+			libhttp.WithRateLimitIdleTimeout(time.Hour),
+		)
+	})
+	serve := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set("X-Api-Key", key)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+	It("returns 429 for requests beyond the burst", func() {
+		for i := 0; i < 3; i++ {
+			Expect(serve("a").Code).To(Equal(http.StatusOK))
+		}
+		resp := serve("a")
+		Expect(resp.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(resp.Header().Get("Retry-After")).To(Equal("60"))
+		var errorResponse libhttp.ErrorResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+		Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeRateLimited))
+	})
+	It("limits keys independently", func() {
+		for i := 0; i < 3; i++ {
+			Expect(serve("a").Code).To(Equal(http.StatusOK))
+		}
+		Expect(serve("a").Code).To(Equal(http.StatusTooManyRequests))
+		Expect(serve("b").Code).To(Equal(http.StatusOK))
+		Expect(atomic.LoadInt32(&created)).To(Equal(int32(2)))
+	})
+	It("is safe for concurrent requests", func() {
+		var allowed int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if serve("a").Code == http.StatusOK {
+					atomic.AddInt32(&allowed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		Expect(atomic.LoadInt32(&allowed)).To(Equal(int32(3)))
+		Expect(atomic.LoadInt32(&created)).To(Equal(int32(1)))
+	})
+	It("removes idle limiters", func() {
+		handler = libhttp.NewRateLimitHandler(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}),
+			func(req *http.Request) string {
+				return req.Header.Get("X-Api-Key")
+			},
+			func(key string) *rate.Limiter {
+				atomic.AddInt32(&created, 1)
+				return rate.NewLimiter(rate.Every(time.Minute), 1)
+			},
+			libhttp.WithRateLimitIdleTimeout(10*time.Millisecond),
+		)
+		Expect(serve("a").Code).To(Equal(http.StatusOK))
+		Expect(serve("a").Code).To(Equal(http.StatusTooManyRequests))
+		time.Sleep(30 * time.Millisecond)
+		Expect(serve("a").Code).To(Equal(http.StatusOK))
+		Expect(atomic.LoadInt32(&created)).To(Equal(int32(2)))
+	})
+})
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// ReadJSONResponse checks the response with CheckResponseIsSuccessful and decodes the JSON body into T.
+// Errors of the check are returned unchanged. The body is always closed.
+func ReadJSONResponse[T any](req *http.Request, resp *http.Response) (T, error) {
+	var result T
+	defer resp.Body.Close()
+	ctx := req.Context()
+	if err := CheckResponseIsSuccessful(req, resp); err != nil {
+		return result, err
+	}
+	contentType := resp.Header.Get(ContentTypeHeaderName)
+	if !isJSONMediaType(contentType) {
+		return result, addRequestResponseToError(
+			errors.Errorf(ctx, "content type '%s' of %s to %s is not %s", contentType, req.Method, req.URL.String(), ApplicationJsonContentType),
+			resp,
+			req,
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, addRequestResponseToError(
+			errors.Wrapf(ctx, err, "decode json of %s to %s failed", req.Method, req.URL.String()),
+			resp,
+			req,
+		)
+	}
+	return result, nil
+}
+
+// isJSONMediaType returns true for application/json and +json types like application/problem+json.
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == ApplicationJsonContentType || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTrackingBody) Close() error {
+	c.closed = true
+	return nil
+}
+
+var _ = Describe("ReadJSONResponse", func() {
+	type user struct {
+		Name string `json:"name"`
+	}
+	var req *http.Request
+	var body *closeTrackingBody
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+		Expect(err).To(BeNil())
+	})
+	newResponse := func(statusCode int, contentType string, content string) *http.Response {
+		body = &closeTrackingBody{Reader: strings.NewReader(content)}
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{libhttp.ContentTypeHeaderName: []string{contentType}},
+			Body:       body,
+		}
+	}
+	It("decodes a valid json response", func() {
+		result, err := libhttp.ReadJSONResponse[user](req, newResponse(http.StatusOK, "application/json; charset=utf-8", `{"name":"Ben"}`))
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(user{Name: "Ben"}))
+		Expect(body.closed).To(BeTrue())
+	})
+	It("returns not found", func() {
+		_, err := libhttp.ReadJSONResponse[user](req, newResponse(http.StatusNotFound, "application/json", `{}`))
+		Expect(errors.Is(err, libhttp.ErrNotFound)).To(BeTrue())
+		Expect(body.closed).To(BeTrue())
+	})
+	It("returns error for non json content type", func() {
+		_, err := libhttp.ReadJSONResponse[user](req, newResponse(http.StatusOK, "text/html", `<html></html>`))
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("text/html"))
+		Expect(body.closed).To(BeTrue())
+	})
+})
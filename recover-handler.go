@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/golang/glog"
+)
+
+// NewRecoverHandler recovers panics of the given handler and responds with a JSON 500.
+// http.ErrAbortHandler is re-panicked to keep the net/http semantic.
+func NewRecoverHandler(next http.Handler) http.Handler {
+	return newRecoverHandler(next, nil)
+}
+
+// newRecoverHandler recovers panics of next, logs them and responds with a JSON 500.
+// onPanic is called with the recovered value before the response is sent, if set.
+func newRecoverHandler(next http.Handler, onPanic func(req *http.Request, recovered any)) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+			glog.Errorf("handle %s request to %s panic: %v\n%s", req.Method, req.URL.Path, r, debug.Stack())
+			if onPanic != nil {
+				onPanic(req, r)
+			}
+			sendInternalServerError(resp, req)
+		}()
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// sendInternalServerError responds with a JSON 500 ErrorResponse.
+func sendInternalServerError(resp http.ResponseWriter, req *http.Request) {
+	if err := SendJSONResponse(
+		req.Context(),
+		resp,
+		ErrorResponse{
+			Error: ErrorDetails{
+				Code:    ErrorCodeInternal,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+		},
+		http.StatusInternalServerError,
+	); err != nil {
+		glog.Warningf("send error response failed: %v", err)
+	}
+}
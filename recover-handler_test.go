@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RecoverHandler", func() {
+	var err error
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	BeforeEach(func() {
+		req, err = http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		resp = httptest.NewRecorder()
+	})
+	Context("panicking handler", func() {
+		BeforeEach(func() {
+			libhttp.NewRecoverHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				panic("banana")
+			})).ServeHTTP(resp, req)
+		})
+		It("returns status code 500", func() {
+			Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		})
+		It("returns json content type", func() {
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+		})
+		It("returns error response", func() {
+			var errorResponse libhttp.ErrorResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+			Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeInternal))
+			Expect(errorResponse.Error.Message).To(Equal("Internal Server Error"))
+		})
+	})
+	Context("successful handler", func() {
+		BeforeEach(func() {
+			libhttp.NewRecoverHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				resp.WriteHeader(http.StatusAccepted)
+			})).ServeHTTP(resp, req)
+		})
+		It("returns status code of handler", func() {
+			Expect(resp.Code).To(Equal(http.StatusAccepted))
+		})
+	})
+	Context("abort handler panic", func() {
+		It("panics again", func() {
+			Expect(func() {
+				libhttp.NewRecoverHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+					panic(http.ErrAbortHandler)
+				})).ServeHTTP(resp, req)
+			}).To(PanicWith(http.ErrAbortHandler))
+		})
+	})
+})
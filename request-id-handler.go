@@ -0,0 +1,43 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+type contextKey string
+
+// RequestIDContextKey is the context key the request ID is stored under.
+const RequestIDContextKey contextKey = "request-id"
+
+// NewRequestIDHandler reads the request ID from the X-Request-ID header or generates a new one,
+// stores it in the request context and echoes it in the response header.
+func NewRequestIDHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get(RequestIDHeaderName)
+		if requestID == "" {
+			var err error
+			requestID, err = generatePassphrase(12)
+			if err != nil {
+				glog.Warningf("generate request id failed: %v", err)
+			}
+		}
+		if requestID != "" {
+			resp.Header().Set(RequestIDHeaderName, requestID)
+			req = req.WithContext(context.WithValue(req.Context(), RequestIDContextKey, requestID))
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// RequestIDFromContext returns the request ID stored by NewRequestIDHandler.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(RequestIDContextKey).(string)
+	return requestID, ok
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequestIDHandler", func() {
+	var err error
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	var contextRequestID string
+	var contextRequestIDFound bool
+	BeforeEach(func() {
+		contextRequestID = ""
+		contextRequestIDFound = false
+		req, err = http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+	})
+	JustBeforeEach(func() {
+		resp = httptest.NewRecorder()
+		libhttp.NewRequestIDHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			contextRequestID, contextRequestIDFound = libhttp.RequestIDFromContext(req.Context())
+		})).ServeHTTP(resp, req)
+	})
+	Context("with incoming request id", func() {
+		BeforeEach(func() {
+			req.Header.Set(libhttp.RequestIDHeaderName, "banana")
+		})
+		It("preserves request id", func() {
+			Expect(resp.Header().Get(libhttp.RequestIDHeaderName)).To(Equal("banana"))
+		})
+		It("stores request id in context", func() {
+			Expect(contextRequestIDFound).To(BeTrue())
+			Expect(contextRequestID).To(Equal("banana"))
+		})
+	})
+	Context("without incoming request id", func() {
+		It("generates request id", func() {
+			Expect(resp.Header().Get(libhttp.RequestIDHeaderName)).To(HaveLen(16))
+		})
+		It("stores generated request id in context", func() {
+			Expect(contextRequestIDFound).To(BeTrue())
+			Expect(contextRequestID).To(Equal(resp.Header().Get(libhttp.RequestIDHeaderName)))
+		})
+	})
+	It("returns false outside of handler", func() {
+		_, ok := libhttp.RequestIDFromContext(req.Context())
+		Expect(ok).To(BeFalse())
+	})
+})
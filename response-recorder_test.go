@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResponseRecorder", func() {
+	var resp *httptest.ResponseRecorder
+	var recorder *libhttp.ResponseRecorder
+	BeforeEach(func() {
+		resp = httptest.NewRecorder()
+		recorder = libhttp.NewResponseRecorder(resp)
+	})
+	It("defaults to status 200", func() {
+		Expect(recorder.StatusCode()).To(Equal(http.StatusOK))
+		Expect(recorder.BytesWritten()).To(Equal(int64(0)))
+	})
+	It("records status code and written bytes", func() {
+		recorder.WriteHeader(http.StatusCreated)
+		recorder.WriteHeader(http.StatusInternalServerError)
+		_, err := recorder.Write([]byte("hello"))
+		Expect(err).To(BeNil())
+		_, err = recorder.Write([]byte(" world"))
+		Expect(err).To(BeNil())
+		Expect(recorder.StatusCode()).To(Equal(http.StatusCreated))
+		Expect(recorder.BytesWritten()).To(Equal(int64(11)))
+		Expect(resp.Body.String()).To(Equal("hello world"))
+	})
+	It("passes flush through", func() {
+		recorder.Flush()
+		Expect(resp.Flushed).To(BeTrue())
+	})
+	It("passes flush through http.ResponseController", func() {
+		Expect(http.NewResponseController(recorder).Flush()).To(Succeed())
+		Expect(resp.Flushed).To(BeTrue())
+	})
+	It("returns ErrNotSupported if the writer can't hijack", func() {
+		_, _, err := recorder.Hijack()
+		Expect(errors.Is(err, http.ErrNotSupported)).To(BeTrue())
+	})
+	It("passes hijack through", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			conn, buf, err := libhttp.NewResponseRecorder(resp).Hijack()
+			Expect(err).To(BeNil())
+			defer conn.Close()
+			_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+			_ = buf.Flush()
+		}))
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		Expect(err).To(BeNil())
+		defer conn.Close()
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		Expect(err).To(BeNil())
+		response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		Expect(err).To(BeNil())
+		body, err := io.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("hijacked"))
+	})
+})
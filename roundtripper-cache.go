@@ -0,0 +1,365 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+//counterfeiter:generate -o mocks/http-cache.go --fake-name HttpCache . Cache
+
+// Cache stores responses of NewRoundTripperCache by URL and the request values of the Vary headers.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+}
+
+// CachedResponse is a response stored in a Cache.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Vary contains the request values of the headers listed in the Vary response header
+	Vary map[string]string
+	// Expires is the time until the response is used without revalidation, zero means always revalidate
+	Expires time.Time
+}
+
+// DefaultMemoryCacheMaxEntries is the number of responses a memory cache keeps by default.
+const DefaultMemoryCacheMaxEntries = 1000
+
+// MemoryCacheOptions configures NewMemoryCache.
+type MemoryCacheOptions struct {
+	// MaxEntries is the number of responses kept, the least recently used response is evicted first
+	MaxEntries int
+}
+
+// WithMemoryCacheMaxEntries sets the number of responses kept by the memory cache.
+func WithMemoryCacheMaxEntries(maxEntries int) func(*MemoryCacheOptions) {
+	return func(options *MemoryCacheOptions) {
+		options.MaxEntries = maxEntries
+	}
+}
+
+// NewMemoryCache returns a Cache in memory which evicts the least recently used response
+// if it holds more than DefaultMemoryCacheMaxEntries.
+func NewMemoryCache(optionFns ...func(*MemoryCacheOptions)) Cache {
+	options := MemoryCacheOptions{
+		MaxEntries: DefaultMemoryCacheMaxEntries,
+	}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = DefaultMemoryCacheMaxEntries
+	}
+	return &memoryCache{
+		maxEntries: options.MaxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+type memoryCache struct {
+	mux        sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// order contains the memoryCacheEntry values, most recently used first
+	order *list.List
+}
+
+type memoryCacheEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+func (m *memoryCache) Get(key string) (*CachedResponse, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(element)
+	return element.Value.(*memoryCacheEntry).response, true
+}
+
+func (m *memoryCache) Set(key string, response *CachedResponse) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if element, ok := m.entries[key]; ok {
+		element.Value.(*memoryCacheEntry).response = response
+		m.order.MoveToFront(element)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&memoryCacheEntry{key: key, response: response})
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// DefaultRoundTripperCacheMaxBodyBytes is the size of the largest response body cached by default.
+const DefaultRoundTripperCacheMaxBodyBytes = 1 << 20
+
+// RoundTripperCacheOptions configures NewRoundTripperCache.
+type RoundTripperCacheOptions struct {
+	// MaxBodyBytes is the size of the largest response body that is cached, larger responses are passed through
+	MaxBodyBytes int64
+}
+
+// WithRoundTripperCacheMaxBodyBytes sets the size of the largest response body that is cached.
+func WithRoundTripperCacheMaxBodyBytes(maxBodyBytes int64) func(*RoundTripperCacheOptions) {
+	return func(options *RoundTripperCacheOptions) {
+		options.MaxBodyBytes = maxBodyBytes
+	}
+}
+
+// NewRoundTripperCache caches responses of GET requests with an ETag, Last-Modified or max-age.
+// Fresh responses are returned without request, stale responses are revalidated with
+// If-None-Match and If-Modified-Since and returned from the cache on 304 Not Modified.
+// Responses with Cache-Control no-store or private and bodies larger than MaxBodyBytes are not cached.
+// Requests with Authorization or Cookie header bypass the cache, because the response is specific to the user.
+// The cache key contains the values of the request headers listed in the Vary header of the response.
+func NewRoundTripperCache(
+	roundTripper http.RoundTripper,
+	cache Cache,
+	optionFns ...func(*RoundTripperCacheOptions),
+) http.RoundTripper {
+	options := RoundTripperCacheOptions{
+		MaxBodyBytes: DefaultRoundTripperCacheMaxBodyBytes,
+	}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return &cacheRoundTripper{
+		roundTripper: roundTripper,
+		cache:        cache,
+		maxBodyBytes: options.MaxBodyBytes,
+	}
+}
+
+type cacheRoundTripper struct {
+	roundTripper http.RoundTripper
+	cache        Cache
+	maxBodyBytes int64
+}
+
+func (c *cacheRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !c.isCacheableRequest(req) {
+		return c.roundTripper.RoundTrip(req)
+	}
+	url := req.URL.String()
+	key := url
+	cached, ok := c.cache.Get(url)
+	if ok && len(cached.Vary) > 0 {
+		// the entry of the url is the last stored variant, its Vary header names select the variant of req
+		key = cacheKey(url, req, cached.Vary)
+		cached, ok = c.cache.Get(key)
+	}
+	if ok && !cached.matchVary(req) {
+		cached = nil
+	}
+	if cached != nil {
+		if time.Now().Before(cached.Expires) {
+			glog.V(3).Infof("serve %s from cache", removeSensibleArgs(key))
+			return cached.response(req), nil
+		}
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := c.roundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_, _ = io.CopyN(io.Discard, resp.Body, 4<<10)
+		_ = resp.Body.Close()
+		glog.V(3).Infof("%s not modified => serve from cache", removeSensibleArgs(url))
+		c.store(url, req, &CachedResponse{
+			StatusCode: cached.StatusCode,
+			Header:     cached.Header,
+			Body:       cached.Body,
+			Vary:       cached.Vary,
+			Expires:    expires(resp.Header),
+		})
+		return cached.response(req), nil
+	}
+	if !isCacheableResponse(resp) {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > c.maxBodyBytes {
+		glog.V(3).Infof("%s body exceeds %d bytes => skip cache", removeSensibleArgs(url), c.maxBodyBytes)
+		resp.Body = &readCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+			Closer: resp.Body,
+		}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.store(url, req, &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Vary:       varyValues(req, resp.Header),
+		Expires:    expires(resp.Header),
+	})
+	return resp, nil
+}
+
+// store sets the response for its variant and as last variant of the url.
+func (c *cacheRoundTripper) store(url string, req *http.Request, response *CachedResponse) {
+	if len(response.Vary) > 0 {
+		c.cache.Set(cacheKey(url, req, response.Vary), response)
+	}
+	c.cache.Set(url, response)
+}
+
+// cacheKey returns the url with the request values of the vary header names.
+func cacheKey(url string, req *http.Request, vary map[string]string) string {
+	names := make([]string, 0, len(vary))
+	for name := range vary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	key.WriteString(url)
+	for _, name := range names {
+		key.WriteString("\n")
+		key.WriteString(name)
+		key.WriteString(": ")
+		key.WriteString(strings.Join(req.Header.Values(name), ", "))
+	}
+	return key.String()
+}
+
+// isCacheableRequest returns false for requests that are not GET, have Cache-Control no-store,
+// carry credentials or are already conditional.
+func (c *cacheRoundTripper) isCacheableRequest(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		!hasCacheControlDirective(req.Header, "no-store") &&
+		req.Header.Get("Authorization") == "" &&
+		req.Header.Get("Cookie") == "" &&
+		req.Header.Get("If-None-Match") == "" &&
+		req.Header.Get("If-Modified-Since") == ""
+}
+
+func isCacheableResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if hasCacheControlDirective(resp.Header, "no-store") || hasCacheControlDirective(resp.Header, "private") {
+		return false
+	}
+	if resp.Header.Get("Vary") == "*" {
+		return false
+	}
+	_, hasMaxAge := maxAge(resp.Header)
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" || hasMaxAge
+}
+
+// expires returns the time until the response is fresh according to max-age.
+func expires(header http.Header) time.Time {
+	if hasCacheControlDirective(header, "no-cache") {
+		return time.Time{}
+	}
+	seconds, ok := maxAge(header)
+	if !ok || seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+func maxAge(header http.Header) (int, bool) {
+	for _, directive := range cacheControlDirectives(header) {
+		if value, ok := strings.CutPrefix(directive, "max-age="); ok {
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				return 0, false
+			}
+			return seconds, true
+		}
+	}
+	return 0, false
+}
+
+func hasCacheControlDirective(header http.Header, name string) bool {
+	for _, directive := range cacheControlDirectives(header) {
+		if directive == name {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheControlDirectives(header http.Header) []string {
+	var result []string
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if directive = strings.ToLower(strings.TrimSpace(directive)); directive != "" {
+				result = append(result, directive)
+			}
+		}
+	}
+	return result
+}
+
+func varyValues(req *http.Request, header http.Header) map[string]string {
+	result := make(map[string]string)
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				result[http.CanonicalHeaderKey(name)] = strings.Join(req.Header.Values(name), ", ")
+			}
+		}
+	}
+	return result
+}
+
+func (c *CachedResponse) matchVary(req *http.Request) bool {
+	for name, value := range c.Vary {
+		if strings.Join(req.Header.Values(name), ", ") != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(c.StatusCode) + " " + http.StatusText(c.StatusCode),
+		StatusCode:    c.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoundTripperCache", func() {
+	var server *httptest.Server
+	var requests []*http.Request
+	var cacheControl string
+	var roundTripper http.RoundTripper
+	BeforeEach(func() {
+		requests = nil
+		cacheControl = ""
+		server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+			if cacheControl != "" {
+				resp.Header().Set("Cache-Control", cacheControl)
+			}
+			resp.Header().Set("ETag", `"v1"`)
+			resp.Header().Set("Vary", "Accept")
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				resp.WriteHeader(http.StatusNotModified)
+				return
+			}
+			_, _ = resp.Write([]byte("hello " + req.Header.Get("Accept")))
+		}))
+		roundTripper = libhttp.NewRoundTripperCache(http.DefaultTransport, libhttp.NewMemoryCache())
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	getWithHeader := func(accept string, header http.Header) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).To(BeNil())
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.Header.Set("Accept", accept)
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		return resp.StatusCode, string(body)
+	}
+	get := func(accept string) (int, string) {
+		return getWithHeader(accept, nil)
+	}
+	It("returns the cached body on 304", func() {
+		statusCode, body := get("text/plain")
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("hello text/plain"))
+
+		statusCode, body = get("text/plain")
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("hello text/plain"))
+
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[0].Header.Get("If-None-Match")).To(BeEmpty())
+		Expect(requests[1].Header.Get("If-None-Match")).To(Equal(`"v1"`))
+	})
+	It("serves fresh responses without request", func() {
+		cacheControl = "max-age=60"
+		get("text/plain")
+		statusCode, body := get("text/plain")
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("hello text/plain"))
+		Expect(requests).To(HaveLen(1))
+	})
+	It("does not cache no-store responses", func() {
+		cacheControl = "no-store"
+		get("text/plain")
+		get("text/plain")
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+	})
+	It("does not use the cached response for other vary values", func() {
+		get("text/plain")
+		statusCode, body := get("application/json")
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(body).To(Equal("hello application/json"))
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+	})
+	It("passes other methods through", func() {
+		req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+		Expect(err).To(BeNil())
+		for i := 0; i < 2; i++ {
+			resp, err := roundTripper.RoundTrip(req)
+			Expect(err).To(BeNil())
+			resp.Body.Close()
+		}
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+	})
+	It("caches each vary variant", func() {
+		cacheControl = "max-age=60"
+		get("text/plain")
+		get("application/json")
+		_, body := get("text/plain")
+		Expect(body).To(Equal("hello text/plain"))
+		_, body = get("application/json")
+		Expect(body).To(Equal("hello application/json"))
+		Expect(requests).To(HaveLen(2))
+	})
+	DescribeTable("bypasses the cache for requests with credentials",
+		func(name string, value string) {
+			cacheControl = "max-age=60"
+			header := http.Header{}
+			header.Set(name, value)
+			getWithHeader("text/plain", header)
+			getWithHeader("text/plain", header)
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+		},
+		Entry("authorization", "Authorization", "Bearer secret"),
+		Entry("cookie", "Cookie", "session=secret"),
+	)
+	It("does not cache private responses", func() {
+		cacheControl = "private, max-age=60"
+		get("text/plain")
+		get("text/plain")
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+	})
+	It("does not cache bodies larger than max body bytes", func() {
+		roundTripper = libhttp.NewRoundTripperCache(
+			http.DefaultTransport,
+			libhttp.NewMemoryCache(),
+			libhttp.WithRoundTripperCacheMaxBodyBytes(5),
+		)
+		cacheControl = "max-age=60"
+		_, body := get("text/plain")
+		Expect(body).To(Equal("hello text/plain"))
+		_, body = get("text/plain")
+		Expect(body).To(Equal("hello text/plain"))
+		Expect(requests).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("MemoryCache", func() {
+	var cache libhttp.Cache
+	BeforeEach(func() {
+		cache = libhttp.NewMemoryCache(libhttp.WithMemoryCacheMaxEntries(2))
+	})
+	It("evicts the least recently used entry", func() {
+		cache.Set("a", &libhttp.CachedResponse{StatusCode: http.StatusOK})
+		cache.Set("b", &libhttp.CachedResponse{StatusCode: http.StatusOK})
+		_, ok := cache.Get("a")
+		Expect(ok).To(BeTrue())
+		cache.Set("c", &libhttp.CachedResponse{StatusCode: http.StatusOK})
+
+		_, ok = cache.Get("a")
+		Expect(ok).To(BeTrue())
+		_, ok = cache.Get("b")
+		Expect(ok).To(BeFalse())
+		_, ok = cache.Get("c")
+		Expect(ok).To(BeTrue())
+	})
+	It("replaces an existing entry", func() {
+		cache.Set("a", &libhttp.CachedResponse{StatusCode: http.StatusOK})
+		cache.Set("a", &libhttp.CachedResponse{StatusCode: http.StatusCreated})
+		response, ok := cache.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(response.StatusCode).To(Equal(http.StatusCreated))
+	})
+})
@@ -0,0 +1,209 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	stderrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+	"github.com/golang/glog"
+)
+
+// ErrCircuitOpen is returned wrapped by the circuit breaker while requests are rejected.
+var ErrCircuitOpen = stderrors.New("circuit open")
+
+// CircuitBreakerState is the state of a circuit breaker.
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed passes all requests
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerOpen rejects all requests until the cooldown is over
+	CircuitBreakerOpen
+	// CircuitBreakerHalfOpen passes a single probe request
+	CircuitBreakerHalfOpen
+)
+
+func (c CircuitBreakerState) String() string {
+	switch c {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerOpen:
+		return "open"
+	case CircuitBreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultCircuitBreakerOptions are used for options that are not set.
+var DefaultCircuitBreakerOptions = CircuitBreakerOptions{
+	FailureThreshold: 5,
+	Window:           time.Minute,
+	Cooldown:         30 * time.Second,
+}
+
+// CircuitBreakerOptions configures NewRoundTripperCircuitBreaker.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// Window is the time the consecutive failures must occur in
+	Window time.Duration
+	// Cooldown is the time the circuit stays open before a probe request is allowed
+	Cooldown time.Duration
+	// CurrentDateTime is used to get the current time, nil uses time.Now
+	CurrentDateTime libtime.CurrentDateTimeGetter
+}
+
+// CircuitBreakerRoundTripper is a http.RoundTripper that exposes the state of its circuit breaker.
+type CircuitBreakerRoundTripper interface {
+	http.RoundTripper
+	State() CircuitBreakerState
+	ConsecutiveFailures() int
+}
+
+// NewRoundTripperCircuitBreaker opens the circuit after FailureThreshold consecutive failures within Window
+// and rejects requests with ErrCircuitOpen for Cooldown. Afterward a single probe request is passed,
+// which closes the circuit on success or opens it again on failure.
+// Transport errors and status codes >= 500 are failures, canceled requests and client errors are not.
+func NewRoundTripperCircuitBreaker(roundTripper http.RoundTripper, options CircuitBreakerOptions) CircuitBreakerRoundTripper {
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = DefaultCircuitBreakerOptions.FailureThreshold
+	}
+	if options.Window <= 0 {
+		options.Window = DefaultCircuitBreakerOptions.Window
+	}
+	if options.Cooldown <= 0 {
+		options.Cooldown = DefaultCircuitBreakerOptions.Cooldown
+	}
+	return &circuitBreakerRoundTripper{
+		roundTripper: roundTripper,
+		options:      options,
+	}
+}
+
+type circuitBreakerRoundTripper struct {
+	roundTripper http.RoundTripper
+	options      CircuitBreakerOptions
+
+	mux                 sync.Mutex
+	state               CircuitBreakerState
+	consecutiveFailures int
+	firstFailure        time.Time
+	openedAt            time.Time
+	probeInFlight       bool
+}
+
+func (c *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := c.allow(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "%s request to %s rejected", req.Method, removeSensibleArgs(req.URL.String()))
+	}
+	resp, err := c.roundTripper.RoundTrip(req)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		c.release()
+	case err != nil || resp.StatusCode >= 500:
+		c.failure()
+	default:
+		c.success()
+	}
+	return resp, err
+}
+
+func (c *circuitBreakerRoundTripper) State() CircuitBreakerState {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.state
+}
+
+func (c *circuitBreakerRoundTripper) ConsecutiveFailures() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.consecutiveFailures
+}
+
+// allow returns ErrCircuitOpen if the request must be rejected.
+func (c *circuitBreakerRoundTripper) allow() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	switch c.state {
+	case CircuitBreakerOpen:
+		if c.now().Sub(c.openedAt) < c.options.Cooldown {
+			return ErrCircuitOpen
+		}
+		glog.V(2).Infof("circuit cooldown over => half-open")
+		c.state = CircuitBreakerHalfOpen
+		c.probeInFlight = true
+		return nil
+	case CircuitBreakerHalfOpen:
+		if c.probeInFlight {
+			return ErrCircuitOpen
+		}
+		c.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (c *circuitBreakerRoundTripper) success() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.state != CircuitBreakerClosed {
+		glog.V(2).Infof("circuit probe succeeded => closed")
+	}
+	c.state = CircuitBreakerClosed
+	c.consecutiveFailures = 0
+	c.probeInFlight = false
+}
+
+func (c *circuitBreakerRoundTripper) failure() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	now := c.now()
+	if c.state == CircuitBreakerHalfOpen {
+		glog.V(2).Infof("circuit probe failed => open")
+		c.open(now)
+		return
+	}
+	if c.consecutiveFailures == 0 || now.Sub(c.firstFailure) > c.options.Window {
+		c.consecutiveFailures = 0
+		c.firstFailure = now
+	}
+	c.consecutiveFailures++
+	if c.state == CircuitBreakerClosed && c.consecutiveFailures >= c.options.FailureThreshold {
+		glog.V(1).Infof("circuit failed %d times => open", c.consecutiveFailures)
+		c.open(now)
+	}
+}
+
+// release frees the probe of a canceled request without changing the state.
+func (c *circuitBreakerRoundTripper) release() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.probeInFlight = false
+}
+
+func (c *circuitBreakerRoundTripper) open(now time.Time) {
+	c.state = CircuitBreakerOpen
+	c.openedAt = now
+	c.probeInFlight = false
+}
+
+func (c *circuitBreakerRoundTripper) now() time.Time {
+	if c.options.CurrentDateTime == nil {
+		return time.Now()
+	}
+	return time.Time(c.options.CurrentDateTime.Now())
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoundTripperCircuitBreaker", func() {
+	var ctx context.Context
+	var baseTransport *mocks.HttpRoundTripper
+	var currentDateTime libtime.CurrentDateTime
+	var roundTripper libhttp.CircuitBreakerRoundTripper
+	BeforeEach(func() {
+		ctx = context.Background()
+		baseTransport = &mocks.HttpRoundTripper{}
+		currentDateTime = libtime.NewCurrentDateTime()
+		currentDateTime.SetNow(libtime.DateTime(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+		roundTripper = libhttp.NewRoundTripperCircuitBreaker(baseTransport, libhttp.CircuitBreakerOptions{
+			FailureThreshold: 3,
+			Window:           time.Minute,
+			Cooldown:         10 * time.Second,
+			CurrentDateTime:  currentDateTime,
+		})
+	})
+	returnStatus := func(statusCode int) {
+		baseTransport.RoundTripReturns(&http.Response{StatusCode: statusCode, Body: http.NoBody}, nil)
+	}
+	roundTrip := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		_, err = roundTripper.RoundTrip(req)
+		return err
+	}
+	advance := func(duration time.Duration) {
+		currentDateTime.SetNow(libtime.DateTime(time.Time(currentDateTime.Now()).Add(duration)))
+	}
+	It("opens after consecutive failures and fails fast", func() {
+		returnStatus(http.StatusBadGateway)
+		for i := 0; i < 3; i++ {
+			Expect(roundTrip()).To(BeNil())
+		}
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerOpen))
+		Expect(roundTripper.ConsecutiveFailures()).To(Equal(3))
+
+		err := roundTrip()
+		Expect(errors.Is(err, libhttp.ErrCircuitOpen)).To(BeTrue())
+		Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+	})
+	It("closes after a successful probe", func() {
+		baseTransport.RoundTripReturns(nil, errors.New("connection refused"))
+		for i := 0; i < 3; i++ {
+			Expect(roundTrip()).NotTo(BeNil())
+		}
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerOpen))
+
+		advance(10 * time.Second)
+		returnStatus(http.StatusOK)
+		Expect(roundTrip()).To(BeNil())
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerClosed))
+		Expect(roundTripper.ConsecutiveFailures()).To(Equal(0))
+	})
+	It("allows a single probe while half-open", func() {
+		returnStatus(http.StatusInternalServerError)
+		for i := 0; i < 3; i++ {
+			Expect(roundTrip()).To(BeNil())
+		}
+		advance(10 * time.Second)
+
+		probeStarted := make(chan struct{})
+		release := make(chan struct{})
+		baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+			close(probeStarted)
+			<-release
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}
+		probeDone := make(chan error, 1)
+		go func() {
+			probeDone <- roundTrip()
+		}()
+		Eventually(probeStarted).Should(BeClosed())
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerHalfOpen))
+		Expect(errors.Is(roundTrip(), libhttp.ErrCircuitOpen)).To(BeTrue())
+
+		close(release)
+		Eventually(probeDone).Should(Receive(BeNil()))
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerClosed))
+	})
+	It("opens again if the probe fails", func() {
+		returnStatus(http.StatusServiceUnavailable)
+		for i := 0; i < 3; i++ {
+			Expect(roundTrip()).To(BeNil())
+		}
+		advance(10 * time.Second)
+		Expect(roundTrip()).To(BeNil())
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerOpen))
+		Expect(errors.Is(roundTrip(), libhttp.ErrCircuitOpen)).To(BeTrue())
+	})
+	It("does not count client errors", func() {
+		returnStatus(http.StatusNotFound)
+		for i := 0; i < 5; i++ {
+			Expect(roundTrip()).To(BeNil())
+		}
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerClosed))
+		Expect(roundTripper.ConsecutiveFailures()).To(Equal(0))
+	})
+	It("does not count failures outside of the window", func() {
+		returnStatus(http.StatusInternalServerError)
+		Expect(roundTrip()).To(BeNil())
+		Expect(roundTrip()).To(BeNil())
+		advance(2 * time.Minute)
+		Expect(roundTrip()).To(BeNil())
+		Expect(roundTripper.State()).To(Equal(libhttp.CircuitBreakerClosed))
+		Expect(roundTripper.ConsecutiveFailures()).To(Equal(1))
+	})
+	It("does not count canceled requests", func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+			cancel()
+			return nil, context.Canceled
+		}
+		Expect(roundTrip()).NotTo(BeNil())
+		Expect(roundTripper.ConsecutiveFailures()).To(Equal(0))
+
+		Expect(roundTrip()).To(Equal(context.Canceled))
+		Expect(baseTransport.RoundTripCallCount()).To(Equal(1))
+	})
+})
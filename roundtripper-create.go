@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// DefaultRoundTripperOptions returns the options used by CreateDefaultRoundTripper.
+func DefaultRoundTripperOptions() RoundTripperOptions {
+	return RoundTripperOptions{
+		Logging:    true,
+		RetryLimit: 5,
+		RetryDelay: time.Second,
+	}
+}
+
+// RoundTripperOptions configures the layers CreateRoundTripper wraps around the transport.
+type RoundTripperOptions struct {
+	// BaseTransport replaces the default transport, nil uses the default transport
+	BaseTransport http.RoundTripper
+	// TLSClientConfig of the default transport, ignored if BaseTransport is set
+	TLSClientConfig *tls.Config
+	// Logging logs each attempt with NewRoundTripperLog
+	Logging bool
+	// RetryLimit is the number of retries, zero disables retries
+	RetryLimit int
+	// RetryDelay is the delay between two attempts
+	RetryDelay time.Duration
+	// RetryBudget limits the total time of all attempts, zero means no limit
+	RetryBudget time.Duration
+	// PerTryTimeout cancels each attempt after the timeout with NewRoundTripperPerTryTimeout, zero disables it
+	PerTryTimeout time.Duration
+	// SkipStatusCodes are returned without retry, nil uses DefaultSkipStatusCodes
+	SkipStatusCodes []int
+	// Metrics records each request with NewRoundTripperMetrics, nil disables metrics
+	Metrics RoundTripperMetrics
+}
+
+// WithRoundTripperBaseTransport replaces the default transport, e.g. to share a connection pool or in tests.
+// The logging, retry and metrics layers are still applied, the TLSClientConfig is ignored.
+func WithRoundTripperBaseTransport(baseTransport http.RoundTripper) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.BaseTransport = baseTransport
+	}
+}
+
+// WithRoundTripperLogging enables or disables logging of each attempt.
+func WithRoundTripperLogging(enabled bool) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.Logging = enabled
+	}
+}
+
+// WithRoundTripperRetry sets the number of retries and the delay between them, a limit of zero disables retries.
+func WithRoundTripperRetry(retryLimit int, retryDelay time.Duration) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.RetryLimit = retryLimit
+		options.RetryDelay = retryDelay
+	}
+}
+
+// WithRoundTripperRetryBudget limits the total time of all attempts.
+// No retry is started if it would begin after the budget, the last response or error is returned instead.
+func WithRoundTripperRetryBudget(retryBudget time.Duration) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.RetryBudget = retryBudget
+	}
+}
+
+// WithRoundTripperPerTryTimeout cancels each attempt after the timeout, so a hung attempt leaves time for retries.
+func WithRoundTripperPerTryTimeout(perTryTimeout time.Duration) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.PerTryTimeout = perTryTimeout
+	}
+}
+
+// WithRoundTripperSkipStatusCodes replaces the status codes returned without retry,
+// e.g. WithRoundTripperSkipStatusCodes(append(DefaultSkipStatusCodes, http.StatusUnprocessableEntity)).
+// It has no effect if retries are disabled.
+func WithRoundTripperSkipStatusCodes(skipStatusCodes []int) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.SkipStatusCodes = skipStatusCodes
+	}
+}
+
+// WithRoundTripperMetrics records each request, e.g. with NewRoundTripperMetricsPrometheus.
+func WithRoundTripperMetrics(metrics RoundTripperMetrics) func(*RoundTripperOptions) {
+	return func(options *RoundTripperOptions) {
+		options.Metrics = metrics
+	}
+}
+
+// CreateRoundTripper returns the default transport wrapped with the configured layers.
+// From outside to inside the layers are metrics, retry, per try timeout and logging:
+// metrics records one request including all retries, logging records every attempt.
+func CreateRoundTripper(optionFns ...func(*RoundTripperOptions)) RoundTripper {
+	options := DefaultRoundTripperOptions()
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	var roundTripper http.RoundTripper = options.BaseTransport
+	if roundTripper == nil {
+		roundTripper = createDefaultTransport(options.TLSClientConfig)
+	}
+	if options.Logging {
+		roundTripper = NewRoundTripperLog(roundTripper)
+	}
+	if options.PerTryTimeout > 0 {
+		roundTripper = NewRoundTripperPerTryTimeout(roundTripper, options.PerTryTimeout)
+	}
+	if options.RetryLimit > 0 {
+		skipStatusCodes := options.SkipStatusCodes
+		if skipStatusCodes == nil {
+			skipStatusCodes = DefaultSkipStatusCodes
+		}
+		roundTripper = &retryRoundTripper{
+			roundTripper:    roundTripper,
+			retryLimit:      options.RetryLimit,
+			retryDelay:      options.RetryDelay,
+			skipStatusCodes: skipStatusCodes,
+			maxBodyBytes:    DefaultRoundTripperRetryMaxBodyBytes,
+			retryBudget:     options.RetryBudget,
+		}
+	}
+	if options.Metrics != nil {
+		roundTripper = NewRoundTripperMetrics(roundTripper, options.Metrics)
+	}
+	return roundTripper
+}
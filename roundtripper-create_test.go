@@ -0,0 +1,193 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateRoundTripper", func() {
+	var server *httptest.Server
+	var serverCounter int32
+	var statusCode int
+	var metrics *mocks.HttpRoundTripperMetrics
+	BeforeEach(func() {
+		serverCounter = 0
+		statusCode = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&serverCounter, 1)
+			resp.WriteHeader(statusCode)
+		}))
+		metrics = &mocks.HttpRoundTripperMetrics{}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	roundTrip := func(roundTripper http.RoundTripper) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).To(BeNil())
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		return resp
+	}
+	It("records metrics", func() {
+		resp := roundTrip(libhttp.CreateRoundTripper(libhttp.WithRoundTripperMetrics(metrics)))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(metrics.TotalCounterIncCallCount()).To(Equal(1))
+		Expect(metrics.SuccessCounterIncCallCount()).To(Equal(1))
+	})
+	It("records one request for all retries", func() {
+		statusCode = http.StatusInternalServerError
+		resp := roundTrip(libhttp.CreateRoundTripper(
+			libhttp.WithRoundTripperMetrics(metrics),
+			libhttp.WithRoundTripperRetry(2, 0),
+		))
+		Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+		Expect(atomic.LoadInt32(&serverCounter)).To(Equal(int32(3)))
+		Expect(metrics.TotalCounterIncCallCount()).To(Equal(1))
+		_, _, recordedStatusCode := metrics.SuccessCounterIncArgsForCall(0)
+		Expect(recordedStatusCode).To(Equal(http.StatusInternalServerError))
+	})
+	It("does not retry if disabled", func() {
+		statusCode = http.StatusInternalServerError
+		roundTrip(libhttp.CreateRoundTripper(
+			libhttp.WithRoundTripperRetry(0, 0),
+			libhttp.WithRoundTripperLogging(false),
+		))
+		Expect(atomic.LoadInt32(&serverCounter)).To(Equal(int32(1)))
+	})
+	Context("with base transport", func() {
+		var baseTransport *mocks.HttpRoundTripper
+		BeforeEach(func() {
+			baseTransport = &mocks.HttpRoundTripper{}
+			baseTransport.RoundTripReturns(&http.Response{
+				StatusCode: http.StatusAccepted,
+				Body:       http.NoBody,
+			}, nil)
+		})
+		It("calls the base transport", func() {
+			resp := roundTrip(libhttp.CreateRoundTripper(
+				libhttp.WithRoundTripperBaseTransport(baseTransport),
+				libhttp.WithRoundTripperMetrics(metrics),
+			))
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(1))
+			Expect(atomic.LoadInt32(&serverCounter)).To(Equal(int32(0)))
+			Expect(metrics.TotalCounterIncCallCount()).To(Equal(1))
+		})
+		It("retries attempts exceeding the per try timeout", func() {
+			baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+				if baseTransport.RoundTripCallCount() == 1 {
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				}
+				return &http.Response{StatusCode: http.StatusAccepted, Body: http.NoBody}, nil
+			}
+			resp := roundTrip(libhttp.CreateRoundTripper(
+				libhttp.WithRoundTripperBaseTransport(baseTransport),
+				libhttp.WithRoundTripperRetry(2, 0),
+				libhttp.WithRoundTripperPerTryTimeout(50*time.Millisecond),
+			))
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(2))
+		})
+		It("retries with the base transport", func() {
+			baseTransport.RoundTripReturns(&http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       http.NoBody,
+			}, nil)
+			resp := roundTrip(libhttp.CreateRoundTripper(
+				libhttp.WithRoundTripperBaseTransport(baseTransport),
+				libhttp.WithRoundTripperRetry(2, 0),
+			))
+			Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+		})
+		It("does not retry default skip status codes", func() {
+			baseTransport.RoundTripReturns(&http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       http.NoBody,
+			}, nil)
+			roundTrip(libhttp.CreateRoundTripper(
+				libhttp.WithRoundTripperBaseTransport(baseTransport),
+				libhttp.WithRoundTripperRetry(2, 0),
+			))
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(1))
+		})
+		It("does not retry custom skip status codes", func() {
+			baseTransport.RoundTripReturns(&http.Response{
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       http.NoBody,
+			}, nil)
+			resp := roundTrip(libhttp.CreateRoundTripper(
+				libhttp.WithRoundTripperBaseTransport(baseTransport),
+				libhttp.WithRoundTripperRetry(2, 0),
+				libhttp.WithRoundTripperSkipStatusCodes([]int{http.StatusUnprocessableEntity}),
+			))
+			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(1))
+		})
+		It("retries status codes not in custom skip status codes", func() {
+			baseTransport.RoundTripReturns(&http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       http.NoBody,
+			}, nil)
+			roundTrip(libhttp.CreateRoundTripper(
+				libhttp.WithRoundTripperBaseTransport(baseTransport),
+				libhttp.WithRoundTripperRetry(2, 0),
+				libhttp.WithRoundTripperSkipStatusCodes([]int{http.StatusUnprocessableEntity}),
+			))
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+		})
+		Context("with retry budget", func() {
+			BeforeEach(func() {
+				baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+					time.Sleep(20 * time.Millisecond)
+					return &http.Response{
+						StatusCode: http.StatusInternalServerError,
+						Body:       http.NoBody,
+					}, nil
+				}
+			})
+			It("stops retrying once the budget is exhausted", func() {
+				resp := roundTrip(libhttp.CreateRoundTripper(
+					libhttp.WithRoundTripperBaseTransport(baseTransport),
+					libhttp.WithRoundTripperRetry(100, 10*time.Millisecond),
+					libhttp.WithRoundTripperRetryBudget(100*time.Millisecond),
+				))
+				Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(baseTransport.RoundTripCallCount()).To(BeNumerically(">", 1))
+				Expect(baseTransport.RoundTripCallCount()).To(BeNumerically("<", 100))
+			})
+			It("returns the last error once the budget is exhausted", func() {
+				baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+					time.Sleep(20 * time.Millisecond)
+					return nil, context.DeadlineExceeded
+				}
+				req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+				Expect(err).To(BeNil())
+				_, err = libhttp.CreateRoundTripper(
+					libhttp.WithRoundTripperBaseTransport(baseTransport),
+					libhttp.WithRoundTripperRetry(100, 10*time.Millisecond),
+					libhttp.WithRoundTripperRetryBudget(100*time.Millisecond),
+				).RoundTrip(req)
+				Expect(err).NotTo(BeNil())
+				Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+				Expect(baseTransport.RoundTripCallCount()).To(BeNumerically("<", 100))
+			})
+		})
+	})
+})
@@ -16,6 +16,13 @@ import (
 	"github.com/bborbe/errors"
 )
 
+// DefaultTransport is an http.Transport configured like the one CreateDefaultRoundTripper creates without TLS client config.
+// CreateDefaultRoundTripper creates its own transport and does not share connections with DefaultTransport.
+// It supports HTTP/2, uses the proxy from the environment and sets dial, TLS handshake and response header timeouts.
+//
+//	roundTripper := libhttp.NewRoundTripperRetry(libhttp.DefaultTransport, 5, time.Second)
+var DefaultTransport http.RoundTripper = createDefaultTransport(nil)
+
 func CreateDefaultRoundTripper() RoundTripper {
 	return createDefaultRoundTripper(nil)
 }
@@ -55,26 +62,26 @@ func CreateTlsClientConfig(ctx context.Context, caCertPath string, clientCertPat
 }
 
 func createDefaultRoundTripper(tlsClientConfig *tls.Config) RoundTripper {
-	return NewRoundTripperRetry(
-		NewRoundTripperLog(
-			&http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: defaultTransportDialContext(&net.Dialer{
-					Timeout:   30 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}),
-				ForceAttemptHTTP2:     true,
-				MaxIdleConns:          100,
-				IdleConnTimeout:       90 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-				ResponseHeaderTimeout: 30 * time.Second,
-				TLSClientConfig:       tlsClientConfig,
-			},
-		),
-		5,
-		time.Second,
-	)
+	return CreateRoundTripper(func(options *RoundTripperOptions) {
+		options.TLSClientConfig = tlsClientConfig
+	})
+}
+
+func createDefaultTransport(tlsClientConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: defaultTransportDialContext(&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		TLSClientConfig:       tlsClientConfig,
+	}
 }
 
 func defaultTransportDialContext(dialer *net.Dialer) func(context.Context, string, string) (net.Conn, error) {
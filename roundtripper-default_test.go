@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DefaultTransport", func() {
+	var server *httptest.Server
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(resp, "ok")
+		}))
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("is configured", func() {
+		Expect(libhttp.DefaultTransport).NotTo(BeNil())
+		transport, ok := libhttp.DefaultTransport.(*http.Transport)
+		Expect(ok).To(BeTrue())
+		Expect(transport.ForceAttemptHTTP2).To(BeTrue())
+		Expect(transport.Proxy).NotTo(BeNil())
+	})
+	It("sends requests", func() {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).To(BeNil())
+		resp, err := libhttp.NewRoundTripperRetry(libhttp.DefaultTransport, 1, 0).RoundTrip(req)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("ok"))
+	})
+})
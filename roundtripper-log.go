@@ -12,15 +12,35 @@ import (
 	"github.com/golang/glog"
 )
 
+// NewRoundTripperLog logs each request at glog verbosity 2.
 func NewRoundTripperLog(tripper http.RoundTripper) http.RoundTripper {
+	return NewRoundTripperLogWithLevel(tripper, 2)
+}
+
+// NewRoundTripperLogWithLevel logs each request at the given glog verbosity.
+func NewRoundTripperLogWithLevel(tripper http.RoundTripper, level glog.Level) http.RoundTripper {
+	return NewRoundTripperLogWithLogger(tripper, level, GlogLogFunc)
+}
+
+// LogFunc writes a log message at the given glog verbosity.
+type LogFunc func(level glog.Level, format string, args ...interface{})
+
+// GlogLogFunc writes the message with glog.V(level).Infof.
+func GlogLogFunc(level glog.Level, format string, args ...interface{}) {
+	glog.V(level).Infof(format, args...)
+}
+
+// NewRoundTripperLogWithLogger logs each request with logFunc at the given verbosity,
+// e.g. to write the log somewhere else than glog.
+func NewRoundTripperLogWithLogger(tripper http.RoundTripper, level glog.Level, logFunc LogFunc) http.RoundTripper {
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		now := libtime.Now()
 		resp, err := tripper.RoundTrip(req)
 		if err != nil {
-			glog.V(2).Infof("%s request to %s in %d ms failed: %v", req.Method, req.URL, time.Since(now).Milliseconds(), err)
+			logFunc(level, "%s request to %s in %d ms failed: %v", req.Method, req.URL, time.Since(now).Milliseconds(), err)
 			return nil, err
 		}
-		glog.V(2).Infof("%s request to %s completed with statusCode %d in %d ms", req.Method, req.URL, resp.StatusCode, time.Since(now).Milliseconds())
+		logFunc(level, "%s request to %s completed with statusCode %d in %d ms", req.Method, req.URL, resp.StatusCode, time.Since(now).Milliseconds())
 		return resp, nil
 	})
 }
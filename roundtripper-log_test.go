@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	"github.com/golang/glog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoundTripperLog", func() {
+	var baseTransport *mocks.HttpRoundTripper
+	var levels []glog.Level
+	var messages []string
+	var logFunc libhttp.LogFunc
+	var req *http.Request
+	BeforeEach(func() {
+		baseTransport = &mocks.HttpRoundTripper{}
+		baseTransport.RoundTripReturns(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil)
+		levels = nil
+		messages = nil
+		logFunc = func(level glog.Level, format string, args ...interface{}) {
+			levels = append(levels, level)
+			messages = append(messages, fmt.Sprintf(format, args...))
+		}
+		var err error
+		req, err = http.NewRequest(http.MethodGet, "http://example.com/logged", nil)
+		Expect(err).To(BeNil())
+	})
+	It("logs a completed request at the given level", func() {
+		resp, err := libhttp.NewRoundTripperLogWithLogger(baseTransport, 1, logFunc).RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(levels).To(Equal([]glog.Level{1}))
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0]).To(HavePrefix("GET request to http://example.com/logged completed with statusCode 200"))
+	})
+	It("logs a failed request at the given level", func() {
+		baseTransport.RoundTripReturns(nil, errors.New("banana"))
+		_, err := libhttp.NewRoundTripperLogWithLogger(baseTransport, 3, logFunc).RoundTrip(req)
+		Expect(err).To(MatchError("banana"))
+		Expect(levels).To(Equal([]glog.Level{3}))
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0]).To(ContainSubstring("failed: banana"))
+	})
+	It("passes the response through with glog", func() {
+		resp, err := libhttp.NewRoundTripperLog(baseTransport).RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+})
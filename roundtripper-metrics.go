@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//counterfeiter:generate -o mocks/http-round-tripper-metrics.go --fake-name HttpRoundTripperMetrics . RoundTripperMetrics
+
+// RoundTripperMetrics records metrics of outgoing requests.
+type RoundTripperMetrics interface {
+	TotalCounterInc(host string, method string)
+	SuccessCounterInc(host string, method string, statusCode int)
+	FailureCounterInc(host string, method string)
+	DurationMeasure(host string, method string, statusCode int, duration time.Duration)
+}
+
+// NewRoundTripperMetrics records total, successful and failed requests and their duration.
+func NewRoundTripperMetrics(
+	roundTripper http.RoundTripper,
+	metrics RoundTripperMetrics,
+) http.RoundTripper {
+	return &metricsRoundTripper{
+		roundTripper: roundTripper,
+		metrics:      metrics,
+	}
+}
+
+type metricsRoundTripper struct {
+	roundTripper http.RoundTripper
+	metrics      RoundTripperMetrics
+}
+
+func (m *metricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	m.metrics.TotalCounterInc(host, req.Method)
+	start := time.Now()
+	resp, err := m.roundTripper.RoundTrip(req)
+	if err != nil {
+		glog.V(3).Infof("%s request to %s failed: %v", req.Method, host, err)
+		m.metrics.FailureCounterInc(host, req.Method)
+		return nil, err
+	}
+	glog.V(3).Infof("%s request to %s completed with status %d", req.Method, host, resp.StatusCode)
+	m.metrics.SuccessCounterInc(host, req.Method, resp.StatusCode)
+	m.metrics.DurationMeasure(host, req.Method, resp.StatusCode, time.Since(start))
+	return resp, nil
+}
+
+// NewRoundTripperMetricsPrometheus registers the client request metrics at the given registerer.
+// It can be called multiple times with the same registerer and panics on any other registration error.
+func NewRoundTripperMetricsPrometheus(reg prometheus.Registerer) RoundTripperMetrics {
+	return &roundTripperMetricsPrometheus{
+		total: registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "http",
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Total number of outgoing http requests.",
+		}, []string{"host", "method"})),
+		success: registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "http",
+			Subsystem: "client",
+			Name:      "responses_total",
+			Help:      "Number of outgoing http requests with a response.",
+		}, []string{"host", "method", "status"})),
+		failure: registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "http",
+			Subsystem: "client",
+			Name:      "failures_total",
+			Help:      "Number of outgoing http requests failed without response.",
+		}, []string{"host", "method"})),
+		duration: registerCollector(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "http",
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of outgoing http requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"host", "method", "status"})),
+	}
+}
+
+type roundTripperMetricsPrometheus struct {
+	total    *prometheus.CounterVec
+	success  *prometheus.CounterVec
+	failure  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func (r *roundTripperMetricsPrometheus) TotalCounterInc(host string, method string) {
+	r.total.WithLabelValues(host, method).Inc()
+}
+
+func (r *roundTripperMetricsPrometheus) SuccessCounterInc(host string, method string, statusCode int) {
+	r.success.WithLabelValues(host, method, strconv.Itoa(statusCode)).Inc()
+}
+
+func (r *roundTripperMetricsPrometheus) FailureCounterInc(host string, method string) {
+	r.failure.WithLabelValues(host, method).Inc()
+}
+
+func (r *roundTripperMetricsPrometheus) DurationMeasure(host string, method string, statusCode int, duration time.Duration) {
+	r.duration.WithLabelValues(host, method, strconv.Itoa(statusCode)).Observe(duration.Seconds())
+}
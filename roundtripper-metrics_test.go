@@ -0,0 +1,43 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("RoundTripperMetricsPrometheus", func() {
+	It("counts requests by host and status", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			resp.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		registry := prometheus.NewRegistry()
+		roundTripper := libhttp.NewRoundTripperMetrics(http.DefaultTransport, libhttp.NewRoundTripperMetricsPrometheus(registry))
+
+		req := httptest.NewRequest(http.MethodGet, server.URL, nil)
+		req.RequestURI = ""
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+
+		host := req.URL.Host
+		Expect(counterValue(registry, "http_client_requests_total", map[string]string{"host": host, "method": "GET"})).To(Equal(1.0))
+		Expect(counterValue(registry, "http_client_responses_total", map[string]string{"host": host, "method": "GET", "status": "204"})).To(Equal(1.0))
+	})
+	It("can be created twice with the same registry", func() {
+		registry := prometheus.NewRegistry()
+		libhttp.NewRoundTripperMetricsPrometheus(registry)
+		Expect(func() {
+			libhttp.NewRoundTripperMetricsPrometheus(registry)
+		}).NotTo(Panic())
+	})
+})
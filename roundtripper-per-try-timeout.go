@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// NewRoundTripperPerTryTimeout cancels each request after the given timeout, independent of the deadline
+// of the request context. Wrapped by NewRoundTripperRetry a hung attempt is canceled and retried
+// instead of consuming the whole deadline. The timeout includes reading the response body.
+func NewRoundTripperPerTryTimeout(roundTripper http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	return &perTryTimeoutRoundTripper{
+		roundTripper: roundTripper,
+		timeout:      timeout,
+	}
+}
+
+type perTryTimeoutRoundTripper struct {
+	roundTripper http.RoundTripper
+	timeout      time.Duration
+}
+
+func (p *perTryTimeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	tryCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	resp, err := p.roundTripper.RoundTrip(req.WithContext(tryCtx))
+	if err != nil {
+		cancel()
+		if ctx.Err() == nil && errors.Is(tryCtx.Err(), context.DeadlineExceeded) {
+			return nil, errors.Wrapf(ctx, err, "%s request to %s exceeded per try timeout of %v", req.Method, removeSensibleArgs(req.URL.String()), p.timeout)
+		}
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{
+		ReadCloser: resp.Body,
+		cancel:     cancel,
+	}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the context of the attempt after the body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnCloseBody) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
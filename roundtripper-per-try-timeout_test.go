@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoundTripperPerTryTimeout", func() {
+	var ctx context.Context
+	var baseTransport *mocks.HttpRoundTripper
+	var req *http.Request
+	BeforeEach(func() {
+		ctx = context.Background()
+		baseTransport = &mocks.HttpRoundTripper{}
+		var err error
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+	})
+	slowThenFast := func(req *http.Request) (*http.Response, error) {
+		if baseTransport.RoundTripCallCount() == 1 {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(time.Second):
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			}
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}
+	It("cancels an attempt exceeding the timeout", func() {
+		baseTransport.RoundTripStub = slowThenFast
+		start := time.Now()
+		_, err := libhttp.NewRoundTripperPerTryTimeout(baseTransport, 50*time.Millisecond).RoundTrip(req)
+		Expect(err).NotTo(BeNil())
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(libhttp.IsRetryError(err)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+	It("succeeds on a fast retry", func() {
+		baseTransport.RoundTripStub = slowThenFast
+		roundTripper := libhttp.NewRoundTripperRetry(
+			libhttp.NewRoundTripperPerTryTimeout(baseTransport, 50*time.Millisecond),
+			2,
+			0,
+		)
+		start := time.Now()
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(baseTransport.RoundTripCallCount()).To(Equal(2))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+	It("keeps the attempt context until the body is closed", func() {
+		var attemptCtx context.Context
+		baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+			attemptCtx = req.Context()
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}
+		resp, err := libhttp.NewRoundTripperPerTryTimeout(baseTransport, time.Minute).RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(attemptCtx.Err()).To(BeNil())
+		Expect(resp.Body.Close()).To(BeNil())
+		Expect(attemptCtx.Err()).To(Equal(context.Canceled))
+	})
+})
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/bborbe/errors"
@@ -18,23 +20,54 @@ import (
 
 const PreventRetryHeaderName = "X-Prevent-Retry"
 
+// DefaultSkipStatusCodes are the error status codes NewRoundTripperRetry returns without retry.
+var DefaultSkipStatusCodes = []int{
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusNotFound,
+}
+
+// DefaultRoundTripperRetryMaxBodyBytes is the size of the largest request body without GetBody
+// that is buffered for retries, larger requests are sent once without retry.
+const DefaultRoundTripperRetryMaxBodyBytes = 1 << 20
+
 // NewRoundTripperRetry wraps a given RoundTripper and retry the httpRequest with a delay between.
 func NewRoundTripperRetry(
 	roundTripper http.RoundTripper,
 	retryLimit int,
 	retryDelay time.Duration,
+) http.RoundTripper {
+	return NewRoundTripperRetryWithSkipStatus(roundTripper, retryLimit, retryDelay, DefaultSkipStatusCodes)
+}
+
+// NewRoundTripperRetryWithSkipStatus is like NewRoundTripperRetry,
+// but returns responses with one of the skipStatusCodes without retry.
+func NewRoundTripperRetryWithSkipStatus(
+	roundTripper http.RoundTripper,
+	retryLimit int,
+	retryDelay time.Duration,
+	skipStatusCodes []int,
 ) http.RoundTripper {
 	return &retryRoundTripper{
-		roundTripper: roundTripper,
-		retryLimit:   retryLimit,
-		retryDelay:   retryDelay,
+		roundTripper:    roundTripper,
+		retryLimit:      retryLimit,
+		retryDelay:      retryDelay,
+		skipStatusCodes: skipStatusCodes,
+		maxBodyBytes:    DefaultRoundTripperRetryMaxBodyBytes,
 	}
 }
 
 type retryRoundTripper struct {
-	roundTripper http.RoundTripper
-	retryLimit   int
-	retryDelay   time.Duration
+	roundTripper    http.RoundTripper
+	retryLimit      int
+	retryDelay      time.Duration
+	skipStatusCodes []int
+	// retryStatusCodes are the only status codes that are retried if set, skipStatusCodes are ignored then
+	retryStatusCodes []int
+	// maxBodyBytes is the size of the largest request body buffered for retries
+	maxBodyBytes int64
+	// retryBudget limits the total time of all attempts, zero means no limit
+	retryBudget time.Duration
 }
 
 func (r *retryRoundTripper) RoundTrip(req *http.Request) (resp *http.Response, err error) {
@@ -45,15 +78,17 @@ func (r *retryRoundTripper) RoundTrip(req *http.Request) (resp *http.Response, e
 
 	ctx := req.Context()
 	retryCounter := 0
+	start := time.Now()
 
-	// TODO: implement me
-	// limit body reader to x mb
-	var body []byte
-	if req.Body != nil {
-		body, err = io.ReadAll(req.Body)
-		if err != nil {
-			return nil, err
-		}
+	getBody, retry, err := requestBodyFactory(req, r.maxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !retry {
+		glog.V(3).Infof("%s request to %s has a body larger than %d bytes => no retry", req.Method, removeSensibleArgs(req.URL.String()), r.maxBodyBytes)
+		reqCloned := req.Clone(ctx)
+		reqCloned.Body, _ = getBody()
+		return r.roundTripper.RoundTrip(reqCloned)
 	}
 
 	for {
@@ -62,12 +97,18 @@ func (r *retryRoundTripper) RoundTrip(req *http.Request) (resp *http.Response, e
 			return nil, ctx.Err()
 		default:
 			reqCloned := req.Clone(ctx)
-			if req.Body != nil {
-				reqCloned.Body = io.NopCloser(bytes.NewBuffer(body))
+			if getBody != nil {
+				reqCloned.Body, err = getBody()
+				if err != nil {
+					return nil, errors.Wrapf(ctx, err, "get body failed")
+				}
 			}
 			resp, err = r.roundTripper.RoundTrip(reqCloned.WithContext(ctx))
 			if err != nil {
 				if IsRetryError(err) && retryCounter < r.retryLimit {
+					if r.budgetExhausted(start) {
+						return nil, errors.Wrapf(ctx, err, "roundtrip failed and retry budget of %v exhausted", r.retryBudget)
+					}
 					glog.V(1).Infof("%s request to %s failed with error: %v => retry", reqCloned.Method, removeSensibleArgs(reqCloned.URL.String()), err)
 					if err := r.delay(ctx); err != nil {
 						return nil, errors.Wrapf(ctx, err, "delay failed")
@@ -78,12 +119,15 @@ func (r *retryRoundTripper) RoundTrip(req *http.Request) (resp *http.Response, e
 				return nil, errors.Wrapf(ctx, err, "roundtrip failed")
 			}
 
-			if !(resp.StatusCode < 400 ||
-				resp.StatusCode == 400 ||
-				resp.StatusCode == 401 ||
-				resp.StatusCode == 404 ||
-				r.retryLimit == retryCounter && resp.StatusCode != 502 && resp.StatusCode != 503 && resp.StatusCode != 504) {
+			if r.retryStatus(resp.StatusCode) && retryCounter < r.retryLimit {
+				if r.budgetExhausted(start) {
+					glog.V(1).Infof("%s request to %s failed with status code %d and retry budget of %v exhausted", reqCloned.Method, removeSensibleArgs(reqCloned.URL.String()), resp.StatusCode, r.retryBudget)
+					return resp, nil
+				}
 				glog.V(1).Infof("%s request to %s failed with status code %d => retry", reqCloned.Method, removeSensibleArgs(reqCloned.URL.String()), resp.StatusCode)
+				// drain a small body to reuse the connection, larger bodies are discarded with it
+				_, _ = io.CopyN(io.Discard, resp.Body, 4<<10)
+				_ = resp.Body.Close()
 				if err := r.delay(ctx); err != nil {
 					return nil, errors.Wrapf(ctx, err, "delay failed")
 				}
@@ -95,6 +139,57 @@ func (r *retryRoundTripper) RoundTrip(req *http.Request) (resp *http.Response, e
 	}
 }
 
+// retryStatus returns true if a response with the given status code is retried.
+func (r *retryRoundTripper) retryStatus(statusCode int) bool {
+	if r.retryStatusCodes != nil {
+		return slices.Contains(r.retryStatusCodes, statusCode)
+	}
+	return statusCode >= 400 && !slices.Contains(r.skipStatusCodes, statusCode)
+}
+
+// requestBodyFactory returns a func that provides the request body for each attempt, or nil without body.
+// The first attempt uses the original body and retries use req.GetBody if available,
+// otherwise the body is buffered in memory up to maxBodyBytes.
+// retry is false if the body is larger, the func then returns the complete body once.
+func requestBodyFactory(req *http.Request, maxBodyBytes int64) (getBody func() (io.ReadCloser, error), retry bool, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true, nil
+	}
+	if req.GetBody != nil {
+		first := true
+		return func() (io.ReadCloser, error) {
+			if first {
+				first = false
+				return req.Body, nil
+			}
+			return req.GetBody()
+		}, true, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes+1))
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, false, err
+	}
+	if int64(len(body)) > maxBodyBytes {
+		return func() (io.ReadCloser, error) {
+			return &readCloser{
+				Reader: io.MultiReader(bytes.NewReader(body), req.Body),
+				Closer: req.Body,
+			}, nil
+		}, false, nil
+	}
+	_ = req.Body.Close()
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}, true, nil
+}
+
+// budgetExhausted returns true if the next attempt would start after the retry budget.
+func (r *retryRoundTripper) budgetExhausted(start time.Time) bool {
+	return r.retryBudget > 0 && time.Since(start)+r.retryDelay >= r.retryBudget
+}
+
 func (r *retryRoundTripper) delay(ctx context.Context) error {
 	if r.retryDelay > 0 {
 		glog.V(3).Infof("sleep for %v", r.retryDelay)
@@ -107,8 +202,22 @@ func (r *retryRoundTripper) delay(ctx context.Context) error {
 	return nil
 }
 
-var removeSensibleArgsRegex = regexp.MustCompile(`hapikey=[^&]+`)
+// sensibleArgNames are the query parameters with credentials, e.g. the passphrase of NewDangerousHandlerWrapper.
+var sensibleArgNames = []string{
+	"hapikey",
+	PassphraseParameterName,
+	ConfirmationParameterName,
+	"password",
+	"secret",
+	"token",
+	"access_token",
+	"apikey",
+	"api_key",
+}
+
+var removeSensibleArgsRegex = regexp.MustCompile(`(?i)((?:^|[?&])(?:` + strings.Join(sensibleArgNames, "|") + `)=)[^&#]*`)
 
+// removeSensibleArgs replaces the values of sensibleArgNames in the url or query with ***.
 func removeSensibleArgs(value string) string {
-	return removeSensibleArgsRegex.ReplaceAllString(value, "hapikey=***")
+	return removeSensibleArgsRegex.ReplaceAllString(value, "${1}***")
 }
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+var _ = Describe("RoundTripperRetry", func() {
+	var ctx context.Context
+	var baseTransport *mocks.HttpRoundTripper
+	var roundTripper http.RoundTripper
+	var bodies []io.ReadCloser
+	var contents []string
+	var body *closeRecorder
+	var req *http.Request
+	BeforeEach(func() {
+		ctx = context.Background()
+		bodies = nil
+		contents = nil
+		baseTransport = &mocks.HttpRoundTripper{}
+		baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+			bodies = append(bodies, req.Body)
+			var content []byte
+			if req.Body != nil {
+				var err error
+				content, err = io.ReadAll(req.Body)
+				Expect(err).To(BeNil())
+			}
+			contents = append(contents, string(content))
+			if len(contents) < 3 {
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}
+		roundTripper = libhttp.NewRoundTripperRetry(baseTransport, 5, 0)
+
+		body = &closeRecorder{Reader: strings.NewReader("payload")}
+		var err error
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com", body)
+		Expect(err).To(BeNil())
+	})
+	It("uses GetBody for retries without buffering", func() {
+		var getBodyCounter int
+		req.GetBody = func() (io.ReadCloser, error) {
+			getBodyCounter++
+			return io.NopCloser(bytes.NewReader([]byte("payload"))), nil
+		}
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(contents).To(Equal([]string{"payload", "payload", "payload"}))
+		Expect(bodies[0]).To(BeIdenticalTo(body))
+		Expect(getBodyCounter).To(Equal(2))
+	})
+	It("buffers the body without GetBody", func() {
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(contents).To(Equal([]string{"payload", "payload", "payload"}))
+		Expect(bodies[0]).NotTo(BeIdenticalTo(body))
+		Expect(body.closed).To(BeTrue())
+	})
+	It("passes requests without body", func() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		resp, err := roundTripper.RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(contents).To(Equal([]string{"", "", ""}))
+	})
+	It("returns the last response after the retry limit", func() {
+		baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		}
+		resp, err := libhttp.NewRoundTripperRetry(baseTransport, 2, 0).RoundTrip(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+	})
+})
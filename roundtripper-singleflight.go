@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/golang/glog"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultRoundTripperSingleFlightMaxBodyBytes is the size of the largest response body shared by default.
+const DefaultRoundTripperSingleFlightMaxBodyBytes = 1 << 20
+
+// RoundTripperSingleFlightOptions configures NewRoundTripperSingleFlight.
+type RoundTripperSingleFlightOptions struct {
+	// MaxBodyBytes is the size of the largest response body that is buffered and shared, larger responses are passed through
+	MaxBodyBytes int64
+}
+
+// WithRoundTripperSingleFlightMaxBodyBytes sets the size of the largest response body that is shared.
+func WithRoundTripperSingleFlightMaxBodyBytes(maxBodyBytes int64) func(*RoundTripperSingleFlightOptions) {
+	return func(options *RoundTripperSingleFlightOptions) {
+		options.MaxBodyBytes = maxBodyBytes
+	}
+}
+
+// NewRoundTripperSingleFlight collapses concurrent GET and HEAD requests with the same URL
+// into one request and returns a copy of the buffered response to every caller.
+// Other methods and requests with Authorization or Cookie header are passed through,
+// so a response is never shared between users. The shared request uses the headers of the
+// first caller, so it must only be used if all callers send the same headers.
+// The shared request is not canceled by the context of a caller, each caller stops waiting if its own context is done.
+// A response with a body larger than MaxBodyBytes is streamed to the first caller, the others send their own request.
+func NewRoundTripperSingleFlight(
+	roundTripper http.RoundTripper,
+	optionFns ...func(*RoundTripperSingleFlightOptions),
+) http.RoundTripper {
+	options := RoundTripperSingleFlightOptions{
+		MaxBodyBytes: DefaultRoundTripperSingleFlightMaxBodyBytes,
+	}
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return &singleFlightRoundTripper{
+		roundTripper: roundTripper,
+		maxBodyBytes: options.MaxBodyBytes,
+	}
+}
+
+type singleFlightRoundTripper struct {
+	roundTripper http.RoundTripper
+	maxBodyBytes int64
+	group        singleflight.Group
+}
+
+// sharedResponse is a response with the body read into memory,
+// or a response with a larger body that is passed to the first caller that claims it.
+type sharedResponse struct {
+	response *http.Response
+	body     []byte
+
+	mux      sync.Mutex
+	stream   bool
+	consumed bool
+}
+
+func (s *singleFlightRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return s.roundTripper.RoundTrip(req)
+	}
+	if req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" {
+		return s.roundTripper.RoundTrip(req)
+	}
+	ctx := req.Context()
+	key := req.Method + " " + req.URL.String()
+	resultChan := s.group.DoChan(key, func() (interface{}, error) {
+		return s.roundTripShared(req.WithContext(context.WithoutCancel(ctx)))
+	})
+	select {
+	case <-ctx.Done():
+		go func() {
+			// close a streamed response nobody else claims
+			if result := <-resultChan; result.Err == nil {
+				if resp := result.Val.(*sharedResponse).claim(); resp != nil {
+					_ = resp.Body.Close()
+				}
+			}
+		}()
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if result.Shared {
+			glog.V(3).Infof("%s request to %s shared", req.Method, removeSensibleArgs(req.URL.String()))
+		}
+		shared := result.Val.(*sharedResponse)
+		if !shared.stream {
+			return shared.clone(req), nil
+		}
+		if resp := shared.claim(); resp != nil {
+			resp.Request = req
+			return resp, nil
+		}
+		glog.V(3).Infof("%s response of %s exceeds %d bytes => send own request", req.Method, removeSensibleArgs(req.URL.String()), s.maxBodyBytes)
+		return s.roundTripper.RoundTrip(req)
+	}
+}
+
+// roundTripShared sends the request and buffers the body up to maxBodyBytes.
+func (s *singleFlightRoundTripper) roundTripShared(req *http.Request) (*sharedResponse, error) {
+	resp, err := s.roundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.maxBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > s.maxBodyBytes {
+		resp.Body = &readCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+			Closer: resp.Body,
+		}
+		return &sharedResponse{response: resp, stream: true}, nil
+	}
+	_ = resp.Body.Close()
+	return &sharedResponse{response: resp, body: body}, nil
+}
+
+// claim returns the streamed response to the first caller and nil to all others.
+func (s *sharedResponse) claim() *http.Response {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.stream || s.consumed {
+		return nil
+	}
+	s.consumed = true
+	return s.response
+}
+
+func (s *sharedResponse) clone(req *http.Request) *http.Response {
+	resp := *s.response
+	resp.Header = s.response.Header.Clone()
+	resp.Trailer = s.response.Trailer.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(s.body))
+	resp.ContentLength = int64(len(s.body))
+	resp.Request = req
+	return &resp
+}
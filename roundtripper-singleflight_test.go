@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoundTripperSingleFlight", func() {
+	var baseTransport *mocks.HttpRoundTripper
+	var release chan struct{}
+	var roundTripper http.RoundTripper
+	BeforeEach(func() {
+		release = make(chan struct{})
+		baseTransport = &mocks.HttpRoundTripper{}
+		baseTransport.RoundTripStub = func(req *http.Request) (*http.Response, error) {
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(bytes.NewBufferString("hello")),
+			}, nil
+		}
+		roundTripper = libhttp.NewRoundTripperSingleFlight(baseTransport)
+	})
+	// startConcurrent sends count requests concurrently and closes done after all responses are read.
+	startConcurrent := func(method string, header http.Header, count int) ([]string, chan struct{}) {
+		var wg sync.WaitGroup
+		bodies := make([]string, count)
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				req, err := http.NewRequest(method, "http://example.com/data", nil)
+				Expect(err).To(BeNil())
+				for name, values := range header {
+					req.Header[name] = values
+				}
+				resp, err := roundTripper.RoundTrip(req)
+				Expect(err).To(BeNil())
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				Expect(err).To(BeNil())
+				bodies[i] = string(body)
+			}(i)
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		return bodies, done
+	}
+	It("calls the round tripper once for concurrent GETs", func() {
+		bodies, done := startConcurrent(http.MethodGet, nil, 10)
+		Eventually(baseTransport.RoundTripCallCount).Should(Equal(1))
+		// give the other callers time to join the in-flight request
+		Consistently(baseTransport.RoundTripCallCount, "50ms").Should(Equal(1))
+		close(release)
+		Eventually(done).Should(BeClosed())
+		Expect(baseTransport.RoundTripCallCount()).To(Equal(1))
+		for _, body := range bodies {
+			Expect(body).To(Equal("hello"))
+		}
+	})
+	It("passes other methods through", func() {
+		close(release)
+		_, done := startConcurrent(http.MethodPost, nil, 3)
+		Eventually(done).Should(BeClosed())
+		Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+	})
+	DescribeTable("passes requests with credentials through",
+		func(name string, value string) {
+			close(release)
+			_, done := startConcurrent(http.MethodGet, http.Header{name: []string{value}}, 3)
+			Eventually(done).Should(BeClosed())
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+		},
+		Entry("authorization", "Authorization", "Bearer secret"),
+		Entry("cookie", "Cookie", "session=secret"),
+	)
+	It("does not cancel the shared request with the context of the first caller", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/data", nil)
+		Expect(err).To(BeNil())
+		firstErr := make(chan error, 1)
+		go func() {
+			_, err := roundTripper.RoundTrip(req)
+			firstErr <- err
+		}()
+		Eventually(baseTransport.RoundTripCallCount).Should(Equal(1))
+		bodies, done := startConcurrent(http.MethodGet, nil, 1)
+		Consistently(baseTransport.RoundTripCallCount, "50ms").Should(Equal(1))
+
+		cancel()
+		Eventually(firstErr).Should(Receive(Equal(context.Canceled)))
+		close(release)
+		Eventually(done).Should(BeClosed())
+		Expect(bodies).To(Equal([]string{"hello"}))
+		Expect(baseTransport.RoundTripArgsForCall(0).Context().Err()).To(BeNil())
+	})
+	Context("response larger than MaxBodyBytes", func() {
+		BeforeEach(func() {
+			roundTripper = libhttp.NewRoundTripperSingleFlight(
+				baseTransport,
+				libhttp.WithRoundTripperSingleFlightMaxBodyBytes(3),
+			)
+		})
+		It("streams the response to one caller and sends own requests for the others", func() {
+			bodies, done := startConcurrent(http.MethodGet, nil, 3)
+			Eventually(baseTransport.RoundTripCallCount).Should(Equal(1))
+			Consistently(baseTransport.RoundTripCallCount, "50ms").Should(Equal(1))
+			close(release)
+			Eventually(done).Should(BeClosed())
+			Expect(baseTransport.RoundTripCallCount()).To(Equal(3))
+			Expect(bodies).To(Equal([]string{"hello", "hello", "hello"}))
+		})
+	})
+})
@@ -0,0 +1,23 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+)
+
+// NewRoundTripperUserAgent sets the User-Agent header if the request has none.
+func NewRoundTripperUserAgent(
+	roundTripper http.RoundTripper,
+	userAgent string,
+) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if userAgent != "" && req.Header.Get("User-Agent") == "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("User-Agent", userAgent)
+		}
+		return roundTripper.RoundTrip(req)
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/bborbe/errors"
+)
+
+// csvFlushRows is the number of rows after which SendCSVResponse flushes the response.
+const csvFlushRows = 100
+
+// SendCSVResponse streams the header and all rows as CSV download with the given fileName.
+// The response is flushed every 100 rows. Because the status is written before the rows,
+// an error while writing can not change the status code anymore.
+func SendCSVResponse(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	header []string,
+	rows func(yield func([]string) bool),
+	fileName string,
+) error {
+	if err := ValidateFilename(ctx, fileName); err != nil {
+		return errors.Wrapf(ctx, err, "validate filename failed")
+	}
+	resp.Header().Set(ContentTypeHeaderName, TextCSVContentType+"; charset=utf-8")
+	resp.Header().Set(ContentDispositionHeaderName, contentDispositionAttachment(fileName))
+	resp.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(resp)
+	responseController := http.NewResponseController(resp)
+	if err := writer.Write(header); err != nil {
+		return errors.Wrapf(ctx, err, "write csv header failed")
+	}
+	var err error
+	counter := 0
+	for row := range rows {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if err = writer.Write(row); err != nil {
+			break
+		}
+		counter++
+		if counter%csvFlushRows == 0 {
+			if err = flushCSV(writer, responseController); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(ctx, err, "write csv row %d failed", counter)
+	}
+	if err := flushCSV(writer, responseController); err != nil {
+		return errors.Wrapf(ctx, err, "flush csv failed")
+	}
+	return nil
+}
+
+func flushCSV(writer *csv.Writer, responseController *http.ResponseController) error {
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	if err := responseController.Flush(); err != nil && !stderrors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendCSVResponse", func() {
+	var ctx context.Context
+	var resp *httptest.ResponseRecorder
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+	})
+	rowsOf := func(rows ...[]string) func(yield func([]string) bool) {
+		return func(yield func([]string) bool) {
+			for _, row := range rows {
+				if !yield(row) {
+					return
+				}
+			}
+		}
+	}
+	It("writes header and rows", func() {
+		err := libhttp.SendCSVResponse(ctx, resp, []string{"id", "name"}, rowsOf(
+			[]string{"1", "apple"},
+			[]string{"2", "pear"},
+		), "export.csv")
+		Expect(err).To(BeNil())
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal("text/csv; charset=utf-8"))
+		Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(Equal(`attachment; filename="export.csv"`))
+		Expect(resp.Body.String()).To(Equal("id,name\n1,apple\n2,pear\n"))
+	})
+	It("quotes fields with commas, quotes and newlines", func() {
+		err := libhttp.SendCSVResponse(ctx, resp, []string{"name"}, rowsOf(
+			[]string{"apple, pear"},
+			[]string{`say "hi"`},
+			[]string{"line\nbreak"},
+		), "export.csv")
+		Expect(err).To(BeNil())
+		Expect(resp.Body.String()).To(Equal("name\n\"apple, pear\"\n\"say \"\"hi\"\"\"\n\"line\nbreak\"\n"))
+	})
+	It("flushes while streaming", func() {
+		err := libhttp.SendCSVResponse(ctx, resp, []string{"id"}, func(yield func([]string) bool) {
+			for i := 0; i < 250; i++ {
+				if !yield([]string{fmt.Sprint(i)}) {
+					return
+				}
+			}
+		}, "export.csv")
+		Expect(err).To(BeNil())
+		Expect(resp.Flushed).To(BeTrue())
+		Expect(strings.Count(resp.Body.String(), "\n")).To(Equal(251))
+	})
+	It("rejects invalid filenames", func() {
+		err := libhttp.SendCSVResponse(ctx, resp, []string{"id"}, rowsOf(), "../export.csv")
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Body.Len()).To(Equal(0))
+		Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(BeEmpty())
+	})
+})
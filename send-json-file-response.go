@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bborbe/errors"
+)
+
+// SendJSONFileResponse writes data as JSON download with the given fileName.
+// The whole payload is marshaled in memory to set the Content-Length.
+func SendJSONFileResponse(ctx context.Context, resp http.ResponseWriter, data any, fileName string, statusCode int) error {
+	if err := ValidateFilename(ctx, fileName); err != nil {
+		return errors.Wrapf(ctx, err, "validate filename failed")
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	resp.Header().Set(ContentTypeHeaderName, ApplicationJsonContentType)
+	resp.Header().Set(ContentDispositionHeaderName, contentDispositionAttachment(fileName))
+	resp.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	resp.WriteHeader(statusCode)
+	if _, err := resp.Write(content); err != nil {
+		return errors.Wrapf(ctx, err, "write json failed")
+	}
+	return nil
+}
+
+// SendJSONFileResponseWithAllowedExtensions is like SendJSONFileResponse but additionally rejects
+// filenames whose extension is not in allowedExtensions (e.g. ".json").
+func SendJSONFileResponseWithAllowedExtensions(ctx context.Context, resp http.ResponseWriter, data any, fileName string, statusCode int, allowedExtensions []string) error {
+	if err := ValidateFilenameExtension(ctx, fileName, allowedExtensions); err != nil {
+		return errors.Wrapf(ctx, err, "validate filename extension failed")
+	}
+	return SendJSONFileResponse(ctx, resp, data, fileName, statusCode)
+}
+
+// SendJSONFileResponseStream is like SendJSONFileResponse but streams the JSON without buffering it.
+// No Content-Length is set, so the response is sent chunked. Because the status is written before encoding,
+// an encode error can not change the status code anymore.
+func SendJSONFileResponseStream(ctx context.Context, resp http.ResponseWriter, data any, fileName string, statusCode int) error {
+	if err := ValidateFilename(ctx, fileName); err != nil {
+		return errors.Wrapf(ctx, err, "validate filename failed")
+	}
+	resp.Header().Set(ContentTypeHeaderName, ApplicationJsonContentType)
+	resp.Header().Set(ContentDispositionHeaderName, contentDispositionAttachment(fileName))
+	resp.WriteHeader(statusCode)
+	if err := json.NewEncoder(resp).Encode(data); err != nil {
+		return errors.Wrapf(ctx, err, "encode json failed")
+	}
+	return nil
+}
+
+// ValidateFilename returns a validation error if fileName is not safe to use in a Content-Disposition header.
+// It rejects path separators, path traversal, quotes and control characters. Unicode is allowed.
+func ValidateFilename(ctx context.Context, fileName string) error {
+	if fileName == "" {
+		return newFilenameValidationError(ctx, "filename is empty")
+	}
+	if fileName == "." || fileName == ".." || strings.Contains(fileName, "..") {
+		return newFilenameValidationError(ctx, "filename '%s' contains path traversal", fileName)
+	}
+	if strings.ContainsAny(fileName, `/\`) {
+		return newFilenameValidationError(ctx, "filename '%s' contains path separator", fileName)
+	}
+	if strings.ContainsRune(fileName, '"') {
+		return newFilenameValidationError(ctx, "filename '%s' contains quote", fileName)
+	}
+	for _, r := range fileName {
+		if unicode.IsControl(r) {
+			return newFilenameValidationError(ctx, "filename '%q' contains control character", fileName)
+		}
+	}
+	if isWindowsReservedName(fileName) {
+		return newFilenameValidationError(ctx, "filename '%s' is a reserved windows device name", fileName)
+	}
+	return nil
+}
+
+// ValidateFilenameExtension returns a validation error if the extension of fileName is not one of allowedExtensions.
+// Extensions are compared case-insensitive and include the leading dot.
+func ValidateFilenameExtension(ctx context.Context, fileName string, allowedExtensions []string) error {
+	extension := path.Ext(fileName)
+	for _, allowedExtension := range allowedExtensions {
+		if extension != "" && strings.EqualFold(extension, allowedExtension) {
+			return nil
+		}
+	}
+	return newFilenameValidationError(ctx, "extension '%s' of filename '%s' is not allowed", extension, fileName)
+}
+
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// isWindowsReservedName returns true if the name without extension is a windows device name like CON or LPT1.
+func isWindowsReservedName(fileName string) bool {
+	name, _, _ := strings.Cut(fileName, ".")
+	return windowsReservedNames[strings.ToUpper(strings.TrimRight(name, " "))]
+}
+
+func newFilenameValidationError(ctx context.Context, format string, args ...any) error {
+	return WrapWithCode(errors.Errorf(ctx, format, args...), ErrorCodeValidation, http.StatusBadRequest)
+}
+
+// contentDispositionAttachment adds a RFC 5987 encoded filename* parameter if fileName contains non-ASCII characters.
+func contentDispositionAttachment(fileName string) string {
+	value := fmt.Sprintf("attachment; filename=\"%s\"", fileName)
+	if isASCII(fileName) {
+		return value
+	}
+	return fmt.Sprintf("%s; filename*=UTF-8''%s", value, encodeRFC5987(fileName))
+}
+
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeRFC5987(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		if isRFC5987AttrChar(b) {
+			sb.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", b)
+	}
+	return sb.String()
+}
+
+func isRFC5987AttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
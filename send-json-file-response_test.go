@@ -0,0 +1,167 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendJSONFileResponse", func() {
+	var ctx context.Context
+	var err error
+	var resp *httptest.ResponseRecorder
+	var fileName string
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+		fileName = "report.json"
+	})
+	JustBeforeEach(func() {
+		err = libhttp.SendJSONFileResponse(ctx, resp, map[string]string{"hello": "world"}, fileName, http.StatusOK)
+	})
+	Context("ascii filename", func() {
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("sets content disposition without filename*", func() {
+			Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(Equal(`attachment; filename="report.json"`))
+		})
+		It("sets content type", func() {
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+		})
+		It("sets content length", func() {
+			Expect(resp.Header().Get("Content-Length")).To(Equal("17"))
+		})
+		It("writes body", func() {
+			Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+		})
+	})
+	Context("unicode filename", func() {
+		BeforeEach(func() {
+			fileName = "bericht-übersicht €.json"
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("sets filename and encoded filename*", func() {
+			Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(Equal(`attachment; filename="bericht-übersicht €.json"; filename*=UTF-8''bericht-%C3%BCbersicht%20%E2%82%AC.json`))
+		})
+	})
+	Context("invalid filename", func() {
+		BeforeEach(func() {
+			fileName = "../etc/passwd"
+		})
+		It("returns error", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("writes nothing", func() {
+			Expect(resp.Body.Len()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("SendJSONFileResponseWithAllowedExtensions", func() {
+	DescribeTable("extension",
+		func(fileName string, valid bool) {
+			resp := httptest.NewRecorder()
+			err := libhttp.SendJSONFileResponseWithAllowedExtensions(context.Background(), resp, []string{"a"}, fileName, http.StatusOK, []string{".json"})
+			if valid {
+				Expect(err).To(BeNil())
+				Expect(resp.Body.String()).To(Equal(`["a"]`))
+			} else {
+				Expect(err).NotTo(BeNil())
+				code, statusCode, _ := libhttp.ResolveError(err)
+				Expect(code).To(Equal(libhttp.ErrorCodeValidation))
+				Expect(statusCode).To(Equal(http.StatusBadRequest))
+				Expect(resp.Body.Len()).To(Equal(0))
+			}
+		},
+		Entry("allowed", "report.json", true),
+		Entry("allowed uppercase", "REPORT.JSON", true),
+		Entry("disallowed", "report.exe", false),
+		Entry("no extension", "report", false),
+		Entry("invalid filename", "../report.json", false),
+	)
+})
+
+var _ = Describe("SendJSONFileResponseStream", func() {
+	var ctx context.Context
+	var err error
+	var resp *httptest.ResponseRecorder
+	var fileName string
+	var data []int
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+		fileName = "numbers.json"
+		data = make([]int, 100000)
+		for i := range data {
+			data[i] = i
+		}
+	})
+	JustBeforeEach(func() {
+		err = libhttp.SendJSONFileResponseStream(ctx, resp, data, fileName, http.StatusOK)
+	})
+	It("returns no error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("sets no content length", func() {
+		Expect(resp.Header().Values("Content-Length")).To(BeEmpty())
+	})
+	It("sets content disposition", func() {
+		Expect(resp.Header().Get(libhttp.ContentDispositionHeaderName)).To(Equal(`attachment; filename="numbers.json"`))
+	})
+	It("encodes large slice", func() {
+		var result []int
+		Expect(json.Unmarshal(resp.Body.Bytes(), &result)).To(Succeed())
+		Expect(result).To(Equal(data))
+	})
+	Context("invalid filename", func() {
+		BeforeEach(func() {
+			fileName = "a/b.json"
+		})
+		It("returns error", func() {
+			Expect(err).NotTo(BeNil())
+		})
+		It("writes nothing", func() {
+			Expect(resp.Body.Len()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("ValidateFilename", func() {
+	DescribeTable("validate",
+		func(fileName string, valid bool) {
+			err := libhttp.ValidateFilename(context.Background(), fileName)
+			if valid {
+				Expect(err).To(BeNil())
+			} else {
+				Expect(err).NotTo(BeNil())
+			}
+		},
+		Entry("simple", "report.json", true),
+		Entry("unicode", "übersicht-日本.json", true),
+		Entry("empty", "", false),
+		Entry("traversal", "../report.json", false),
+		Entry("slash", "a/report.json", false),
+		Entry("backslash", `a\report.json`, false),
+		Entry("quote", `re"port.json`, false),
+		Entry("newline", "report\r\nX-Injected: yes", false),
+		Entry("windows device CON", "CON", false),
+		Entry("windows device lowercase with extension", "con.json", false),
+		Entry("windows device LPT9", "LPT9.txt", false),
+		Entry("windows device COM1", "COM1", false),
+		Entry("windows device NUL", "nul.tar.gz", false),
+		Entry("windows device prefix", "console.json", true),
+		Entry("windows device suffix", "my-prn.json", true),
+	)
+})
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// SendJSONResponse encodes data as JSON and writes it with the given statusCode.
+func SendJSONResponse(ctx context.Context, resp http.ResponseWriter, data any, statusCode int) error {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	return writeJSONResponse(ctx, resp, content, statusCode)
+}
+
+// SendJSONResponseIndent is like SendJSONResponse but pretty-prints the JSON with the given indent.
+// Like SendJSONResponse it writes no trailing newline.
+func SendJSONResponseIndent(ctx context.Context, resp http.ResponseWriter, data any, statusCode int, indent string) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(data); err != nil {
+		return errors.Wrapf(ctx, err, "encode json failed")
+	}
+	return writeJSONResponse(ctx, resp, bytes.TrimSuffix(buf.Bytes(), []byte("\n")), statusCode)
+}
+
+// SendJSONResponseWithoutHTMLEscape is like SendJSONResponse but keeps <, > and & unescaped in strings.
+func SendJSONResponseWithoutHTMLEscape(ctx context.Context, resp http.ResponseWriter, data any, statusCode int) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return errors.Wrapf(ctx, err, "encode json failed")
+	}
+	return writeJSONResponse(ctx, resp, bytes.TrimSuffix(buf.Bytes(), []byte("\n")), statusCode)
+}
+
+// ServeJSONWithETag is like SendJSONResponse but sets a strong ETag of the JSON content.
+// If the If-None-Match header of a GET or HEAD request matches and statusCode is 2xx, it writes 304 Not Modified without body.
+// Other methods and status codes ignore If-None-Match, because the handler already ran.
+func ServeJSONWithETag(ctx context.Context, resp http.ResponseWriter, req *http.Request, data any, statusCode int) error {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	sum := sha256.Sum256(content)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	resp.Header().Set("ETag", etag)
+	if isSafeReadMethod(req.Method) && statusCode >= 200 && statusCode < 300 && matchETag(req.Header.Get("If-None-Match"), etag) {
+		resp.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	return writeJSONResponse(ctx, resp, content, statusCode)
+}
+
+// matchETag returns true if the If-None-Match value contains etag or is *.
+// Weak tags match as If-None-Match uses the weak comparison.
+func matchETag(ifNoneMatch string, etag string) bool {
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// isSafeReadMethod returns true for GET and HEAD, the only methods answered with 304 Not Modified.
+func isSafeReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func writeJSONResponse(ctx context.Context, resp http.ResponseWriter, content []byte, statusCode int) error {
+	resp.Header().Set(ContentTypeHeaderName, ApplicationJsonContentType)
+	resp.WriteHeader(statusCode)
+	if _, err := resp.Write(content); err != nil {
+		return errors.Wrapf(ctx, err, "write json failed")
+	}
+	return nil
+}
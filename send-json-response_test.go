@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendJSONResponse", func() {
+	var ctx context.Context
+	var err error
+	var resp *httptest.ResponseRecorder
+	var data any
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+		data = map[string]any{
+			"hello": "world",
+		}
+	})
+	Context("SendJSONResponse", func() {
+		JustBeforeEach(func() {
+			err = libhttp.SendJSONResponse(ctx, resp, data, http.StatusCreated)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("writes status code", func() {
+			Expect(resp.Code).To(Equal(http.StatusCreated))
+		})
+		It("writes content type", func() {
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+		})
+		It("writes compact json", func() {
+			Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+		})
+	})
+	Context("SendJSONResponseIndent", func() {
+		JustBeforeEach(func() {
+			err = libhttp.SendJSONResponseIndent(ctx, resp, data, http.StatusOK, "  ")
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("writes status code", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+		It("writes content type", func() {
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+		})
+		It("writes indented json", func() {
+			Expect(resp.Body.String()).To(Equal("{\n  \"hello\": \"world\"\n}"))
+		})
+		Context("nil value", func() {
+			BeforeEach(func() {
+				data = nil
+			})
+			It("writes null", func() {
+				Expect(resp.Body.String()).To(Equal("null"))
+			})
+		})
+		Context("unencodable value", func() {
+			BeforeEach(func() {
+				data = make(chan int)
+			})
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+			It("writes nothing", func() {
+				Expect(resp.Body.Len()).To(Equal(0))
+			})
+		})
+	})
+	Context("html escaping", func() {
+		BeforeEach(func() {
+			data = map[string]string{
+				"value": "<b>&",
+			}
+		})
+		It("escapes html by default", func() {
+			Expect(libhttp.SendJSONResponse(ctx, resp, data, http.StatusOK)).To(Succeed())
+			Expect(resp.Body.String()).To(Equal(`{"value":"\u003cb\u003e\u0026"}`))
+		})
+		It("keeps raw characters without html escape", func() {
+			Expect(libhttp.SendJSONResponseWithoutHTMLEscape(ctx, resp, data, http.StatusOK)).To(Succeed())
+			Expect(resp.Body.String()).To(Equal(`{"value":"<b>&"}`))
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationJsonContentType))
+		})
+	})
+	Context("ServeJSONWithETag", func() {
+		var req *http.Request
+		var statusCode int
+		BeforeEach(func() {
+			req = httptest.NewRequest(http.MethodGet, "/data", nil)
+			statusCode = http.StatusOK
+		})
+		JustBeforeEach(func() {
+			err = libhttp.ServeJSONWithETag(ctx, resp, req, data, statusCode)
+		})
+		It("writes body and ETag", func() {
+			Expect(err).To(BeNil())
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+			Expect(resp.Header().Get("ETag")).To(MatchRegexp(`^"[0-9a-f]{64}"$`))
+		})
+		Context("matching If-None-Match", func() {
+			BeforeEach(func() {
+				first := httptest.NewRecorder()
+				Expect(libhttp.ServeJSONWithETag(ctx, first, req, data, http.StatusOK)).To(Succeed())
+				req.Header.Set("If-None-Match", `"other", `+first.Header().Get("ETag"))
+			})
+			It("writes 304 without body", func() {
+				Expect(err).To(BeNil())
+				Expect(resp.Code).To(Equal(http.StatusNotModified))
+				Expect(resp.Body.Len()).To(Equal(0))
+				Expect(resp.Header().Get("ETag")).NotTo(BeEmpty())
+			})
+			Context("error status code", func() {
+				BeforeEach(func() {
+					statusCode = http.StatusNotFound
+				})
+				It("writes status code and body", func() {
+					Expect(resp.Code).To(Equal(http.StatusNotFound))
+					Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+				})
+			})
+		})
+		Context("If-None-Match * on PUT", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest(http.MethodPut, "/data", nil)
+				req.Header.Set("If-None-Match", "*")
+				statusCode = http.StatusCreated
+			})
+			It("ignores If-None-Match", func() {
+				Expect(err).To(BeNil())
+				Expect(resp.Code).To(Equal(http.StatusCreated))
+				Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+			})
+		})
+		Context("other If-None-Match", func() {
+			BeforeEach(func() {
+				req.Header.Set("If-None-Match", `"other"`)
+			})
+			It("writes body", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Body.String()).To(Equal(`{"hello":"world"}`))
+			})
+		})
+	})
+})
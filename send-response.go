@@ -0,0 +1,34 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/bborbe/errors"
+)
+
+// SendResponse writes body with the given content type and statusCode.
+func SendResponse(ctx context.Context, resp http.ResponseWriter, contentType string, body []byte, statusCode int) error {
+	resp.Header().Set(ContentTypeHeaderName, contentType)
+	resp.WriteHeader(statusCode)
+	if _, err := resp.Write(body); err != nil {
+		return errors.Wrapf(ctx, err, "write body failed")
+	}
+	return nil
+}
+
+// SendReaderResponse streams the content of reader with the given content type and statusCode.
+// A read error after the status is written is returned, but can not change the status code anymore.
+func SendReaderResponse(ctx context.Context, resp http.ResponseWriter, contentType string, reader io.Reader, statusCode int) error {
+	resp.Header().Set(ContentTypeHeaderName, contentType)
+	resp.WriteHeader(statusCode)
+	if _, err := io.Copy(resp, reader); err != nil {
+		return errors.Wrapf(ctx, err, "copy body failed")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing/iotest"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendResponse", func() {
+	var ctx context.Context
+	var err error
+	var resp *httptest.ResponseRecorder
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+	})
+	Context("SendResponse", func() {
+		var body []byte
+		JustBeforeEach(func() {
+			err = libhttp.SendResponse(ctx, resp, "text/csv", body, http.StatusOK)
+		})
+		Context("text body", func() {
+			BeforeEach(func() {
+				body = []byte("a,b\n1,2\n")
+			})
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("sets content type", func() {
+				Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal("text/csv"))
+			})
+			It("writes status code", func() {
+				Expect(resp.Code).To(Equal(http.StatusOK))
+			})
+			It("writes body", func() {
+				Expect(resp.Body.String()).To(Equal("a,b\n1,2\n"))
+			})
+		})
+		Context("empty body", func() {
+			BeforeEach(func() {
+				body = nil
+			})
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("writes empty body", func() {
+				Expect(resp.Body.Len()).To(Equal(0))
+			})
+		})
+	})
+	Context("SendReaderResponse", func() {
+		var reader io.Reader
+		JustBeforeEach(func() {
+			err = libhttp.SendReaderResponse(ctx, resp, "application/yaml", reader, http.StatusCreated)
+		})
+		Context("successful reader", func() {
+			BeforeEach(func() {
+				reader = strings.NewReader("hello: world\n")
+			})
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("sets content type", func() {
+				Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal("application/yaml"))
+			})
+			It("writes status code", func() {
+				Expect(resp.Code).To(Equal(http.StatusCreated))
+			})
+			It("writes body", func() {
+				Expect(resp.Body.String()).To(Equal("hello: world\n"))
+			})
+		})
+		Context("reader fails mid-stream", func() {
+			var readErr error
+			BeforeEach(func() {
+				readErr = errors.New(ctx, "banana")
+				reader = io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(readErr))
+			})
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+				Expect(errors.Is(err, readErr)).To(BeTrue())
+			})
+			It("writes partial body", func() {
+				Expect(resp.Body.String()).To(Equal("partial"))
+			})
+		})
+	})
+})
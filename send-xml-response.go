@@ -0,0 +1,28 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/bborbe/errors"
+)
+
+// SendXMLResponse encodes data as XML with a leading XML declaration and writes it with the given statusCode.
+// A nil data writes only the declaration.
+func SendXMLResponse(ctx context.Context, resp http.ResponseWriter, data any, statusCode int) error {
+	content, err := xml.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal xml failed")
+	}
+	resp.Header().Set(ContentTypeHeaderName, ApplicationXMLContentType)
+	resp.WriteHeader(statusCode)
+	if _, err := resp.Write(append([]byte(xml.Header), content...)); err != nil {
+		return errors.Wrapf(ctx, err, "write xml failed")
+	}
+	return nil
+}
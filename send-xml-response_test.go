@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type xmlOrder struct {
+	XMLName xml.Name `xml:"order"`
+	ID      string   `xml:"id,attr"`
+	Item    string   `xml:"item"`
+}
+
+var _ = Describe("SendXMLResponse", func() {
+	var ctx context.Context
+	var resp *httptest.ResponseRecorder
+	BeforeEach(func() {
+		ctx = context.Background()
+		resp = httptest.NewRecorder()
+	})
+	It("writes a struct as xml", func() {
+		Expect(libhttp.SendXMLResponse(ctx, resp, xmlOrder{ID: "42", Item: "apple & pear"}, http.StatusCreated)).To(Succeed())
+		Expect(resp.Code).To(Equal(http.StatusCreated))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(Equal(libhttp.ApplicationXMLContentType))
+		Expect(resp.Body.String()).To(Equal(xml.Header + `<order id="42"><item>apple &amp; pear</item></order>`))
+	})
+	It("writes only the declaration for nil", func() {
+		Expect(libhttp.SendXMLResponse(ctx, resp, nil, http.StatusOK)).To(Succeed())
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal(xml.Header))
+	})
+	It("returns an error for unencodable values", func() {
+		err := libhttp.SendXMLResponse(ctx, resp, map[string]string{"hello": "world"}, http.StatusOK)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("marshal xml failed"))
+		Expect(resp.Body.Len()).To(Equal(0))
+		Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(BeEmpty())
+	})
+})
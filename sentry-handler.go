@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	libsentry "github.com/bborbe/sentry"
+	"github.com/getsentry/sentry-go"
+)
+
+// NewSentryHandler recovers panics of the given handler, captures them to Sentry and responds with a JSON 500.
+// http.ErrAbortHandler is re-panicked to keep the net/http semantic.
+func NewSentryHandler(next http.Handler, sentryClient libsentry.Client, optionFns ...func(*SentryOptions)) http.Handler {
+	options := buildSentryOptions(optionFns...)
+	return newRecoverHandler(next, func(req *http.Request, recovered any) {
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+		if !options.shouldReport(err) {
+			return
+		}
+		scope := sentry.NewScope()
+		scope.SetRequest(req)
+		sentryClient.CaptureException(
+			err,
+			&sentry.EventHint{
+				Context:            req.Context(),
+				Request:            req,
+				RecoveredException: recovered,
+			},
+			scope,
+		)
+	})
+}
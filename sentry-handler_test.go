@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	"github.com/bborbe/http/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SentryHandler", func() {
+	var sentryClient *mocks.SentryClient
+	var resp *httptest.ResponseRecorder
+	var req *http.Request
+	BeforeEach(func() {
+		sentryClient = &mocks.SentryClient{}
+		resp = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	})
+	Context("panicking handler", func() {
+		BeforeEach(func() {
+			libhttp.NewSentryHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				panic("banana")
+			}), sentryClient).ServeHTTP(resp, req)
+		})
+		It("captures exception", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+			err, hint, _ := sentryClient.CaptureExceptionArgsForCall(0)
+			Expect(err).To(MatchError("panic: banana"))
+			Expect(hint.Request).To(Equal(req))
+		})
+		It("returns status code 500", func() {
+			Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+	Context("panicking with ignored error", func() {
+		BeforeEach(func() {
+			libhttp.NewSentryHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				panic(context.Canceled)
+			}), sentryClient).ServeHTTP(resp, req)
+		})
+		It("captures nothing", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+		It("returns status code 500", func() {
+			Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+	Context("successful handler", func() {
+		BeforeEach(func() {
+			libhttp.NewSentryHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				resp.WriteHeader(http.StatusAccepted)
+			}), sentryClient).ServeHTTP(resp, req)
+		})
+		It("captures nothing", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(0))
+		})
+		It("returns status code of handler", func() {
+			Expect(resp.Code).To(Equal(http.StatusAccepted))
+		})
+	})
+})
@@ -0,0 +1,31 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// NewServerH2C serves HTTP/2 without TLS (h2c), e.g. inside a service mesh.
+// HTTP/1.1 requests are still served.
+func NewServerH2C(addr string, router http.Handler, optionFns ...func(*ServerOptions)) run.Func {
+	return func(ctx context.Context) error {
+		options := buildServerOptions(optionFns...)
+		http2Server := &http2.Server{
+			IdleTimeout: options.IdleTimeout,
+		}
+		server := CreateHttpServer(addr, h2c.NewHandler(router, http2Server), options)
+		if err := http2.ConfigureServer(server, http2Server); err != nil {
+			return errors.Wrapf(ctx, err, "configure http2 server failed")
+		}
+		return runServer(ctx, server, options, server.ListenAndServe)
+	}
+}
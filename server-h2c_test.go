@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/http2"
+)
+
+var _ = Describe("Http Server H2C", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var port int
+	var err error
+	var serverDone chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		serverDone = make(chan error, 1)
+		port, err = freePort()
+		Expect(err).To(BeNil())
+		httpServer := libhttp.NewServerH2C(
+			fmt.Sprintf("localhost:%d", port),
+			http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				fmt.Fprintf(writer, "proto %s", request.Proto)
+			}),
+		)
+		go func() {
+			serverDone <- httpServer.Run(ctx)
+		}()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("serves http2 over cleartext and shuts down", func() {
+		client := &http.Client{
+			Transport: &http2.Transport{
+				AllowHTTP: true,
+				DialTLSContext: func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, network, addr)
+				},
+			},
+		}
+		var resp *http.Response
+		Eventually(func() error {
+			resp, err = client.Get(fmt.Sprintf("http://localhost:%d", port))
+			return err
+		}).Should(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.ProtoMajor).To(Equal(2))
+		content, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(string(content)).To(Equal("proto HTTP/2.0"))
+
+		cancel()
+		Eventually(serverDone).Should(Receive(BeNil()))
+	})
+})
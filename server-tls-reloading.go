@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/bborbe/run"
+	"github.com/golang/glog"
+)
+
+// NewServerTLSReloading serves TLS and reloads the certificate from disk if the files change,
+// e.g. after a rotation by cert-manager. The files are checked every reloadInterval,
+// a reloadInterval of zero or less loads the certificate only once.
+// If a reload fails the last good certificate is served.
+func NewServerTLSReloading(addr string, router http.Handler, serverCertPath string, serverKeyPath string, reloadInterval time.Duration, optionFns ...func(*ServerOptions)) run.Func {
+	return func(ctx context.Context) error {
+		reloader := &certificateReloader{
+			certPath: serverCertPath,
+			keyPath:  serverKeyPath,
+		}
+		if err := reloader.Reload(ctx); err != nil {
+			return errors.Wrapf(ctx, err, "load certificate failed")
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		if reloadInterval > 0 {
+			go reloader.Watch(ctx, reloadInterval)
+		}
+
+		options := buildServerOptions(optionFns...)
+		tlsConfig := ensureTLSConfig(&options)
+		if tlsConfig.MinVersion == 0 {
+			tlsConfig.MinVersion = tls.VersionTLS12
+		}
+		tlsConfig.GetCertificate = reloader.GetCertificate
+		server := CreateHttpServer(addr, router, options)
+		return runServer(ctx, server, options, func() error {
+			return server.ListenAndServeTLS("", "")
+		})
+	}
+}
+
+// certificateReloader caches the certificate and reloads it if the modification time of cert or key changes.
+type certificateReloader struct {
+	certPath string
+	keyPath  string
+
+	mux         sync.Mutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func (c *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.certificate, nil
+}
+
+// Watch reloads the certificate every interval until ctx is canceled. The interval must be positive.
+func (c *certificateReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(ctx); err != nil {
+				glog.Warningf("reload certificate %s failed, keep serving last certificate: %v", c.certPath, err)
+			}
+		}
+	}
+}
+
+// Reload loads cert and key if one of the files changed since the last successful load.
+func (c *certificateReloader) Reload(ctx context.Context) error {
+	certInfo, err := os.Stat(c.certPath)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "stat certificate %s failed", c.certPath)
+	}
+	keyInfo, err := os.Stat(c.keyPath)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "stat key %s failed", c.keyPath)
+	}
+
+	c.mux.Lock()
+	unchanged := c.certificate != nil && certInfo.ModTime().Equal(c.certModTime) && keyInfo.ModTime().Equal(c.keyModTime)
+	c.mux.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "load key pair %s failed", c.certPath)
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.certificate = &certificate
+	c.certModTime = certInfo.ModTime()
+	c.keyModTime = keyInfo.ModTime()
+	glog.V(2).Infof("certificate %s loaded", c.certPath)
+	return nil
+}
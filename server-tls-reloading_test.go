@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Http Server TLS reloading", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var addr string
+	var certPath string
+	var keyPath string
+	var serverDone chan error
+	var reloadInterval time.Duration
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		serverDone = make(chan error, 1)
+		port, err := freePort()
+		Expect(err).To(BeNil())
+		addr = fmt.Sprintf("localhost:%d", port)
+
+		dir := GinkgoT().TempDir()
+		certPath = filepath.Join(dir, "tls.crt")
+		keyPath = filepath.Join(dir, "tls.key")
+		writeCertificate(certPath, keyPath, "first", time.Now().Add(-time.Hour))
+		reloadInterval = 10 * time.Millisecond
+	})
+	JustBeforeEach(func() {
+		httpServer := libhttp.NewServerTLSReloading(
+			addr,
+			http.NotFoundHandler(),
+			certPath,
+			keyPath,
+			reloadInterval,
+		)
+		ctx, serverDone := ctx, serverDone
+		go func() {
+			serverDone <- httpServer.Run(ctx)
+		}()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("serves the rotated certificate", func() {
+		Eventually(func() (string, error) {
+			return servedCommonName(addr)
+		}).Should(Equal("first"))
+
+		writeCertificate(certPath, keyPath, "second", time.Now())
+		Eventually(func() (string, error) {
+			return servedCommonName(addr)
+		}).Should(Equal("second"))
+
+		cancel()
+		Eventually(serverDone).Should(Receive(BeNil()))
+	})
+	It("keeps the last certificate if reload fails", func() {
+		Eventually(func() (string, error) {
+			return servedCommonName(addr)
+		}).Should(Equal("first"))
+
+		Expect(os.WriteFile(certPath, []byte("invalid"), 0600)).To(Succeed())
+		Expect(os.Chtimes(certPath, time.Now(), time.Now())).To(Succeed())
+		Consistently(func() (string, error) {
+			return servedCommonName(addr)
+		}, 100*time.Millisecond).Should(Equal("first"))
+	})
+	Context("reload interval zero", func() {
+		BeforeEach(func() {
+			reloadInterval = 0
+		})
+		It("serves the first certificate without reload", func() {
+			Eventually(func() (string, error) {
+				return servedCommonName(addr)
+			}).Should(Equal("first"))
+
+			writeCertificate(certPath, keyPath, "second", time.Now())
+			Consistently(func() (string, error) {
+				return servedCommonName(addr)
+			}, 100*time.Millisecond).Should(Equal("first"))
+
+			cancel()
+			Eventually(serverDone).Should(Receive(BeNil()))
+		})
+	})
+})
+
+func servedCommonName(addr string) (string, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr, &tls.Config{
+		InsecureSkipVerify: true, // #nosec G402 self-signed test certificate
+	})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func writeCertificate(certPath string, keyPath string, commonName string, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(BeNil())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	Expect(err).To(BeNil())
+	Expect(os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	Expect(os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)).To(Succeed())
+	Expect(os.Chtimes(certPath, modTime, modTime)).To(Succeed())
+	Expect(os.Chtimes(keyPath, modTime, modTime)).To(Succeed())
+}
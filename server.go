@@ -7,70 +7,414 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/bborbe/errors"
 	"github.com/bborbe/run"
 	"github.com/golang/glog"
 )
 
-func NewServerWithPort(port int, router http.Handler) run.Func {
+// ServerOptions configures the http.Server created by NewServer and NewServerTLS.
+type ServerOptions struct {
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	MaxHeaderBytes  int
+	ShutdownTimeout time.Duration
+	TLSConfig       *tls.Config
+	// OnShutdown is called after the server shutdown completed or timed out
+	OnShutdown func(err error)
+	// ShutdownHooks are executed in order after the server shutdown within the ShutdownTimeout
+	ShutdownHooks []ShutdownHook
+	// ErrorLogWriter receives the server error log with TLS handshake errors filtered out.
+	// Defaults to the output of the standard logger.
+	ErrorLogWriter io.Writer
+	// UnixSocketMode is the file mode of the socket created by NewServerUnix
+	UnixSocketMode os.FileMode
+}
+
+// ShutdownHook is executed on server shutdown, e.g. to close database pools or flush buffers.
+type ShutdownHook func(ctx context.Context) error
+
+// DefaultServerOptions returns options without read, write and idle timeouts
+// and 30 seconds to drain in-flight requests on shutdown.
+func DefaultServerOptions() ServerOptions {
+	return ServerOptions{
+		MaxHeaderBytes:  http.DefaultMaxHeaderBytes,
+		ShutdownTimeout: 30 * time.Second,
+		UnixSocketMode:  0660,
+	}
+}
+
+// WithShutdownCallback sets a callback invoked with the result of the server shutdown.
+// The error is non-nil if in-flight requests could not be drained within the ShutdownTimeout.
+func WithShutdownCallback(onShutdown func(err error)) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		options.OnShutdown = onShutdown
+	}
+}
+
+// RegisterOnShutdown appends hooks executed after the server shutdown.
+// Hook errors are aggregated and returned by the server run.Func.
+func RegisterOnShutdown(hooks ...ShutdownHook) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		options.ShutdownHooks = append(options.ShutdownHooks, hooks...)
+	}
+}
+
+// WithReadTimeout sets the maximum duration for reading the entire request.
+// Values <= 0 fall back to the default.
+func WithReadTimeout(timeout time.Duration) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		if timeout <= 0 {
+			options.ReadTimeout = DefaultServerOptions().ReadTimeout
+			return
+		}
+		options.ReadTimeout = timeout
+	}
+}
+
+// WithWriteTimeout sets the maximum duration before timing out writes of the response.
+// Values <= 0 fall back to the default.
+func WithWriteTimeout(timeout time.Duration) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		if timeout <= 0 {
+			options.WriteTimeout = DefaultServerOptions().WriteTimeout
+			return
+		}
+		options.WriteTimeout = timeout
+	}
+}
+
+// WithIdleTimeout sets the maximum time to wait for the next request on keep-alive connections.
+// Values <= 0 fall back to the default.
+func WithIdleTimeout(timeout time.Duration) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		if timeout <= 0 {
+			options.IdleTimeout = DefaultServerOptions().IdleTimeout
+			return
+		}
+		options.IdleTimeout = timeout
+	}
+}
+
+// WithShutdownTimeout sets the time to drain in-flight requests on shutdown.
+// Values <= 0 fall back to the default.
+func WithShutdownTimeout(timeout time.Duration) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		if timeout <= 0 {
+			options.ShutdownTimeout = DefaultServerOptions().ShutdownTimeout
+			return
+		}
+		options.ShutdownTimeout = timeout
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of request headers.
+// Values <= 0 fall back to the default.
+func WithMaxHeaderBytes(maxHeaderBytes int) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		if maxHeaderBytes <= 0 {
+			options.MaxHeaderBytes = DefaultServerOptions().MaxHeaderBytes
+			return
+		}
+		options.MaxHeaderBytes = maxHeaderBytes
+	}
+}
+
+// WithErrorLogWriter sets the base writer of the server error log.
+func WithErrorLogWriter(writer io.Writer) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		options.ErrorLogWriter = writer
+	}
+}
+
+// WithUnixSocketMode sets the file mode of the socket created by NewServerUnix.
+func WithUnixSocketMode(mode os.FileMode) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		options.UnixSocketMode = mode
+	}
+}
+
+// WithTLSMinVersion sets the minimum TLS version accepted by the server, e.g. tls.VersionTLS13.
+func WithTLSMinVersion(version uint16) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		ensureTLSConfig(options).MinVersion = version
+	}
+}
+
+// WithTLSMaxVersion sets the maximum TLS version accepted by the server.
+func WithTLSMaxVersion(version uint16) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		ensureTLSConfig(options).MaxVersion = version
+	}
+}
+
+// WithCipherSuites restricts the TLS 1.0-1.2 cipher suites of the server.
+// TLS 1.3 cipher suites are not configurable.
+func WithCipherSuites(cipherSuites ...uint16) func(*ServerOptions) {
+	return func(options *ServerOptions) {
+		ensureTLSConfig(options).CipherSuites = cipherSuites
+	}
+}
+
+// ensureTLSConfig returns the TLSConfig of the options and creates it with TLS 1.2 as minimum version if missing.
+// An existing TLSConfig is cloned, so the config of the caller is never modified.
+func ensureTLSConfig(options *ServerOptions) *tls.Config {
+	if options.TLSConfig == nil {
+		options.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+		return options.TLSConfig
+	}
+	options.TLSConfig = options.TLSConfig.Clone()
+	return options.TLSConfig
+}
+
+// NewServerWithPort listens on all interfaces on the given port.
+// The returned run.Func fails immediately if the port is not in the range 0 to 65535.
+func NewServerWithPort(port int, router http.Handler, optionFns ...func(*ServerOptions)) run.Func {
+	if port < 0 || port > 65535 {
+		return func(ctx context.Context) error {
+			return errors.Errorf(ctx, "invalid port %d, must be between 0 and 65535", port)
+		}
+	}
 	return NewServer(
 		fmt.Sprintf(":%d", port),
 		router,
+		optionFns...,
 	)
 }
 
-func NewServer(addr string, router http.Handler) run.Func {
+func NewServer(addr string, router http.Handler, optionFns ...func(*ServerOptions)) run.Func {
 	return func(ctx context.Context) error {
+		options := buildServerOptions(optionFns...)
+		server := CreateHttpServer(addr, router, options)
+		return runServer(ctx, server, options, server.ListenAndServe)
+	}
+}
 
-		server := &http.Server{
-			Addr:      addr,
-			Handler:   router,
-			TLSConfig: nil,
-		}
-		go func() {
-			select {
-			case <-ctx.Done():
-				if err := server.Shutdown(ctx); err != nil {
-					glog.Warningf("shutdown failed: %v", err)
-				}
+// NewServerWithListener serves on the given listener, e.g. for socket activation or port 0 in tests.
+// The listener is closed on shutdown.
+func NewServerWithListener(listener net.Listener, router http.Handler, optionFns ...func(*ServerOptions)) run.Func {
+	return func(ctx context.Context) error {
+		options := buildServerOptions(optionFns...)
+		server := CreateHttpServer(listener.Addr().String(), router, options)
+		return runServer(ctx, server, options, func() error {
+			return server.Serve(listener)
+		})
+	}
+}
+
+// NewServerUnix serves on a unix domain socket, e.g. for the communication with a sidecar.
+// A stale socket file left by a previous run is removed. A regular file or a socket another process
+// still accepts connections on is an error.
+// The socket file gets the UnixSocketMode before it becomes visible at socketPath and is removed on shutdown.
+func NewServerUnix(socketPath string, router http.Handler, optionFns ...func(*ServerOptions)) run.Func {
+	return func(ctx context.Context) error {
+		options := buildServerOptions(optionFns...)
+		if err := removeStaleSocket(ctx, socketPath); err != nil {
+			return err
+		}
+		listener, err := listenUnix(ctx, socketPath, options.UnixSocketMode)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+				glog.Warningf("remove unix socket %s failed: %v", socketPath, err)
 			}
 		}()
-		err := server.ListenAndServe()
-		if errors.Is(err, http.ErrServerClosed) {
-			glog.V(0).Info(err)
+		server := CreateHttpServer(socketPath, router, options)
+		return runServer(ctx, server, options, func() error {
+			return server.Serve(listener)
+		})
+	}
+}
+
+// listenUnix creates the socket in a private 0700 directory next to socketPath, sets the mode and
+// renames it to socketPath afterward. So the socket is never reachable with the permissions of the umask.
+func listenUnix(ctx context.Context, socketPath string, mode os.FileMode) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(socketPath), ".sock")
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create directory for unix socket %s failed", socketPath)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			glog.Warningf("remove directory %s failed: %v", dir, err)
+		}
+	}()
+	tmpPath := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "listen on unix socket %s failed", socketPath)
+	}
+	// the socket file is renamed and removed by NewServerUnix
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		_ = listener.Close()
+		return nil, errors.Wrapf(ctx, err, "chmod unix socket %s failed", socketPath)
+	}
+	if err := os.Rename(tmpPath, socketPath); err != nil {
+		_ = listener.Close()
+		return nil, errors.Wrapf(ctx, err, "rename unix socket to %s failed", socketPath)
+	}
+	return listener, nil
+}
+
+// removeStaleSocket removes the socket file at the given path if present and nobody accepts connections on it.
+func removeStaleSocket(ctx context.Context, socketPath string) error {
+	fileInfo, err := os.Lstat(socketPath)
+	if err != nil {
+		if os.IsNotExist(err) {
 			return nil
 		}
-		return errors.Wrapf(ctx, err, "httpServer failed")
+		return errors.Wrapf(ctx, err, "stat unix socket %s failed", socketPath)
+	}
+	if fileInfo.Mode()&os.ModeSocket == 0 {
+		return errors.Errorf(ctx, "%s exists and is not a unix socket", socketPath)
+	}
+	if conn, err := net.DialTimeout("unix", socketPath, time.Second); err == nil {
+		_ = conn.Close()
+		return errors.Errorf(ctx, "unix socket %s is in use", socketPath)
+	}
+	glog.V(2).Infof("remove stale unix socket %s", socketPath)
+	if err := os.Remove(socketPath); err != nil {
+		return errors.Wrapf(ctx, err, "remove stale unix socket %s failed", socketPath)
 	}
+	return nil
 }
 
-func NewServerTLS(addr string, router http.Handler, serverCertPath string, serverKeyPath string) run.Func {
+func NewServerTLS(addr string, router http.Handler, serverCertPath string, serverKeyPath string, optionFns ...func(*ServerOptions)) run.Func {
 	return func(ctx context.Context) error {
-		server := &http.Server{
-			Addr:     addr,
-			Handler:  router,
-			ErrorLog: log.New(NewSkipErrorWriter(log.Writer()), "", log.LstdFlags),
-		}
-		go func() {
-			select {
-			case <-ctx.Done():
-				if err := server.Shutdown(ctx); err != nil {
-					glog.Warningf("shutdown failed: %v", err)
-				}
-			}
-		}()
-		err := server.ListenAndServeTLS(serverCertPath, serverKeyPath)
-		if errors.Is(err, http.ErrServerClosed) {
-			glog.V(0).Info(err)
-			return nil
+		options := buildServerOptions(optionFns...)
+		if ensureTLSConfig(&options).MinVersion == 0 {
+			options.TLSConfig.MinVersion = tls.VersionTLS12
+		}
+		server := CreateHttpServer(addr, router, options)
+		return runServer(ctx, server, options, func() error {
+			return server.ListenAndServeTLS(serverCertPath, serverKeyPath)
+		})
+	}
+}
+
+// CreateHttpServer creates a http.Server configured with the given options.
+// The error log skips noisy TLS handshake errors.
+func CreateHttpServer(addr string, router http.Handler, options ServerOptions) *http.Server {
+	errorLogWriter := options.ErrorLogWriter
+	if errorLogWriter == nil {
+		errorLogWriter = log.Writer()
+	}
+	return &http.Server{
+		Addr:           addr,
+		Handler:        router,
+		ReadTimeout:    options.ReadTimeout,
+		WriteTimeout:   options.WriteTimeout,
+		IdleTimeout:    options.IdleTimeout,
+		MaxHeaderBytes: options.MaxHeaderBytes,
+		TLSConfig:      options.TLSConfig,
+		ErrorLog:       log.New(NewSkipErrorWriter(errorLogWriter), "", log.LstdFlags),
+	}
+}
+
+func buildServerOptions(optionFns ...func(*ServerOptions)) ServerOptions {
+	options := DefaultServerOptions()
+	for _, optionFn := range optionFns {
+		optionFn(&options)
+	}
+	return options
+}
+
+// runServer serves until ctx is canceled and shuts the server down gracefully afterward.
+func runServer(ctx context.Context, server *http.Server, options ServerOptions, serve func() error) error {
+	connections := &connectionTracker{}
+	server.ConnState = connections.ConnState
+
+	serveDone := make(chan struct{})
+	shutdownDone := make(chan struct{})
+	var hooksErr error
+	go func() {
+		defer close(shutdownDone)
+		select {
+		case <-serveDone:
+		case <-ctx.Done():
+			hooksErr = shutdownServer(server, options, connections)
+		}
+	}()
+	err := serve()
+	close(serveDone)
+	<-shutdownDone
+	if errors.Is(err, http.ErrServerClosed) {
+		glog.V(0).Info(err)
+		if hooksErr != nil {
+			return errors.Wrapf(ctx, hooksErr, "shutdown hooks failed")
+		}
+		return nil
+	}
+	return errors.Wrapf(ctx, err, "httpServer failed")
+}
+
+// shutdownServer drains the server and runs the shutdown hooks afterward.
+// It returns the aggregated errors of the hooks.
+func shutdownServer(server *http.Server, options ServerOptions, connections *connectionTracker) error {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), options.ShutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		glog.Warningf("shutdown failed with %d active connections: %v", connections.Active(), err)
+	}
+	if options.OnShutdown != nil {
+		options.OnShutdown(err)
+	}
+	var hookErrs []error
+	for _, hook := range options.ShutdownHooks {
+		if err := hook(shutdownCtx); err != nil {
+			glog.Warningf("shutdown hook failed: %v", err)
+			hookErrs = append(hookErrs, err)
+		}
+	}
+	return errors.Join(hookErrs...)
+}
+
+// connectionTracker keeps track of the connection states to report active connections on shutdown.
+type connectionTracker struct {
+	mux         sync.Mutex
+	connections map[net.Conn]http.ConnState
+}
+
+func (c *connectionTracker) ConnState(conn net.Conn, state http.ConnState) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.connections == nil {
+		c.connections = make(map[net.Conn]http.ConnState)
+	}
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(c.connections, conn)
+	default:
+		c.connections[conn] = state
+	}
+}
+
+func (c *connectionTracker) Active() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	var counter int
+	for _, state := range c.connections {
+		if state == http.StateActive {
+			counter++
 		}
-		return errors.Wrapf(ctx, err, "httpServer failed")
 	}
+	return counter
 }
 
 func NewSkipErrorWriter(writer io.Writer) io.Writer {
@@ -5,11 +5,17 @@
 package http_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
 	libhttp "github.com/bborbe/http"
 	"github.com/bborbe/run"
@@ -39,6 +45,14 @@ var _ = Describe("Http Server", func() {
 			defer GinkgoRecover()
 			Expect(httpServer.Run(ctx)).To(BeNil())
 		}()
+		// the server listens asynchronously, wait until it accepts connections
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}).Should(Succeed())
 	})
 	AfterEach(func() {
 		cancel()
@@ -54,6 +68,408 @@ var _ = Describe("Http Server", func() {
 	})
 })
 
+var _ = Describe("Http Server with port", func() {
+	DescribeTable("rejects invalid ports",
+		func(port int) {
+			err := libhttp.NewServerWithPort(port, http.NotFoundHandler()).Run(context.Background())
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("invalid port %d", port)))
+		},
+		Entry("negative", -1),
+		Entry("too large", 70000),
+	)
+	It("serves on a valid port", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		port, err := freePort()
+		Expect(err).To(BeNil())
+		serverDone := make(chan error, 1)
+		go func() {
+			serverDone <- libhttp.NewServerWithPort(port, http.NotFoundHandler()).Run(ctx)
+		}()
+		Eventually(func() error {
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d", port))
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}).Should(Succeed())
+		cancel()
+		Eventually(serverDone).Should(Receive(BeNil()))
+	})
+})
+
+var _ = Describe("Http Server shutdown", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var port int
+	var err error
+	var handlerStarted chan struct{}
+	var releaseHandler chan struct{}
+	var shutdownErr chan error
+	var serverDone chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		handlerStarted = make(chan struct{})
+		releaseHandler = make(chan struct{})
+		shutdownErr = make(chan error, 1)
+		serverDone = make(chan error, 1)
+
+		port, err = freePort()
+		Expect(err).To(BeNil())
+
+		httpServer := libhttp.NewServer(
+			fmt.Sprintf("localhost:%d", port),
+			http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				close(handlerStarted)
+				<-releaseHandler
+				fmt.Fprint(writer, "ok")
+			}),
+			func(options *libhttp.ServerOptions) {
+				options.ShutdownTimeout = 100 * time.Millisecond
+			},
+			libhttp.WithShutdownCallback(func(err error) {
+				shutdownErr <- err
+			}),
+		)
+		go func() {
+			serverDone <- httpServer.Run(ctx)
+		}()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("calls shutdown callback with error if draining times out", func() {
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+		go func() {
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%d", port))
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		Eventually(handlerStarted).Should(BeClosed())
+		cancel()
+		var err error
+		Eventually(shutdownErr).Should(Receive(&err))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Eventually(serverDone).Should(Receive(BeNil()))
+		close(releaseHandler)
+	})
+})
+
+var _ = Describe("Http Server shutdown hooks", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var port int
+	var err error
+	var calls []string
+	var hookErr error
+	var serverDone chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		calls = nil
+		hookErr = nil
+		serverDone = make(chan error, 1)
+		port, err = freePort()
+		Expect(err).To(BeNil())
+	})
+	JustBeforeEach(func() {
+		httpServer := libhttp.NewServer(
+			fmt.Sprintf("localhost:%d", port),
+			http.NotFoundHandler(),
+			libhttp.RegisterOnShutdown(
+				func(ctx context.Context) error {
+					calls = append(calls, "first")
+					return nil
+				},
+				func(ctx context.Context) error {
+					calls = append(calls, "second")
+					return hookErr
+				},
+			),
+			libhttp.RegisterOnShutdown(
+				func(ctx context.Context) error {
+					calls = append(calls, "third")
+					return nil
+				},
+			),
+		)
+		go func() {
+			serverDone <- httpServer.Run(ctx)
+		}()
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+		cancel()
+	})
+	It("runs hooks in registration order", func() {
+		Eventually(serverDone).Should(Receive(BeNil()))
+		Expect(calls).To(Equal([]string{"first", "second", "third"}))
+	})
+	Context("hook fails", func() {
+		BeforeEach(func() {
+			hookErr = errors.New("banana")
+		})
+		It("returns hook error", func() {
+			var err error
+			Eventually(serverDone).Should(Receive(&err))
+			Expect(err).To(MatchError(ContainSubstring("banana")))
+			Expect(calls).To(Equal([]string{"first", "second", "third"}))
+		})
+	})
+})
+
+var _ = Describe("Http Server with listener", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var listener net.Listener
+	var err error
+	var serverDone chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		serverDone = make(chan error, 1)
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		httpServer := libhttp.NewServerWithListener(
+			listener,
+			http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				fmt.Fprint(writer, "ok")
+			}),
+		)
+		go func() {
+			serverDone <- httpServer.Run(ctx)
+		}()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("serves on assigned port and shuts down cleanly", func() {
+		resp, err := http.Get(fmt.Sprintf("http://%s", listener.Addr().String()))
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		content, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(string(content)).To(Equal("ok"))
+
+		cancel()
+		Eventually(serverDone).Should(Receive(BeNil()))
+		_, err = net.Dial("tcp", listener.Addr().String())
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Http Server unix socket", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var socketPath string
+	var serverDone chan error
+	var client *http.Client
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		serverDone = make(chan error, 1)
+		dir, err := os.MkdirTemp("", "http-unix")
+		Expect(err).To(BeNil())
+		DeferCleanup(os.RemoveAll, dir)
+		socketPath = filepath.Join(dir, "server.sock")
+		client = &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	startServer := func() {
+		httpServer := libhttp.NewServerUnix(
+			socketPath,
+			http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				fmt.Fprint(writer, "ok")
+			}),
+		)
+		ctx, serverDone := ctx, serverDone
+		go func() {
+			serverDone <- httpServer.Run(ctx)
+		}()
+	}
+	get := func() (string, error) {
+		resp, err := client.Get("http://unix/")
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		return string(content), err
+	}
+	It("serves on the socket and removes it on shutdown", func() {
+		startServer()
+		Eventually(get).Should(Equal("ok"))
+
+		fileInfo, err := os.Stat(socketPath)
+		Expect(err).To(BeNil())
+		Expect(fileInfo.Mode().Perm()).To(Equal(os.FileMode(0660)))
+
+		cancel()
+		Eventually(serverDone).Should(Receive(BeNil()))
+		_, err = os.Stat(socketPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+	It("removes a stale socket", func() {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).To(BeNil())
+		listener.(*net.UnixListener).SetUnlinkOnClose(false)
+		Expect(listener.Close()).To(BeNil())
+
+		startServer()
+		Eventually(get).Should(Equal("ok"))
+	})
+	It("does not remove a socket in use", func() {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).To(BeNil())
+		defer listener.Close()
+
+		err = libhttp.NewServerUnix(socketPath, http.NotFoundHandler()).Run(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("is in use"))
+		_, err = os.Stat(socketPath)
+		Expect(err).To(BeNil())
+	})
+	It("does not leave temporary files next to the socket", func() {
+		startServer()
+		Eventually(get).Should(Equal("ok"))
+
+		entries, err := os.ReadDir(filepath.Dir(socketPath))
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name()).To(Equal("server.sock"))
+	})
+	It("does not remove a regular file", func() {
+		Expect(os.WriteFile(socketPath, []byte("data"), 0600)).To(BeNil())
+		err := libhttp.NewServerUnix(socketPath, http.NotFoundHandler()).Run(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("is not a unix socket"))
+		content, err := os.ReadFile(socketPath)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("data"))
+	})
+})
+
+var _ = Describe("ServerOptions", func() {
+	var options libhttp.ServerOptions
+	var defaults libhttp.ServerOptions
+	BeforeEach(func() {
+		defaults = libhttp.DefaultServerOptions()
+		options = libhttp.DefaultServerOptions()
+	})
+	It("sets read timeout", func() {
+		libhttp.WithReadTimeout(5 * time.Second)(&options)
+		Expect(options.ReadTimeout).To(Equal(5 * time.Second))
+	})
+	It("sets write timeout", func() {
+		libhttp.WithWriteTimeout(6 * time.Second)(&options)
+		Expect(options.WriteTimeout).To(Equal(6 * time.Second))
+	})
+	It("sets idle timeout", func() {
+		libhttp.WithIdleTimeout(7 * time.Second)(&options)
+		Expect(options.IdleTimeout).To(Equal(7 * time.Second))
+	})
+	It("sets shutdown timeout", func() {
+		libhttp.WithShutdownTimeout(8 * time.Second)(&options)
+		Expect(options.ShutdownTimeout).To(Equal(8 * time.Second))
+	})
+	It("sets max header bytes", func() {
+		libhttp.WithMaxHeaderBytes(4096)(&options)
+		Expect(options.MaxHeaderBytes).To(Equal(4096))
+	})
+	DescribeTable("falls back to defaults for invalid values",
+		func(value int64) {
+			libhttp.WithReadTimeout(time.Minute)(&options)
+			libhttp.WithWriteTimeout(time.Minute)(&options)
+			libhttp.WithIdleTimeout(time.Minute)(&options)
+			libhttp.WithShutdownTimeout(time.Minute)(&options)
+			libhttp.WithMaxHeaderBytes(4096)(&options)
+
+			libhttp.WithReadTimeout(time.Duration(value))(&options)
+			libhttp.WithWriteTimeout(time.Duration(value))(&options)
+			libhttp.WithIdleTimeout(time.Duration(value))(&options)
+			libhttp.WithShutdownTimeout(time.Duration(value))(&options)
+			libhttp.WithMaxHeaderBytes(int(value))(&options)
+			Expect(options).To(Equal(defaults))
+		},
+		Entry("zero", int64(0)),
+		Entry("negative", int64(-1)),
+	)
+})
+
+var _ = Describe("ServerOptions TLS", func() {
+	var options libhttp.ServerOptions
+	BeforeEach(func() {
+		options = libhttp.DefaultServerOptions()
+	})
+	It("has no TLS config by default", func() {
+		Expect(options.TLSConfig).To(BeNil())
+	})
+	It("defaults min version to TLS 1.2", func() {
+		libhttp.WithTLSMaxVersion(tls.VersionTLS13)(&options)
+		Expect(options.TLSConfig).NotTo(BeNil())
+		Expect(options.TLSConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(options.TLSConfig.MaxVersion).To(Equal(uint16(tls.VersionTLS13)))
+	})
+	It("sets min version", func() {
+		libhttp.WithTLSMinVersion(tls.VersionTLS13)(&options)
+		Expect(options.TLSConfig.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+	})
+	It("sets cipher suites", func() {
+		libhttp.WithCipherSuites(
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		)(&options)
+		Expect(options.TLSConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(options.TLSConfig.CipherSuites).To(Equal([]uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		}))
+	})
+	It("augments a copy of an existing TLS config", func() {
+		existing := &tls.Config{ServerName: "example.com"}
+		options.TLSConfig = existing
+		libhttp.WithTLSMinVersion(tls.VersionTLS13)(&options)
+		Expect(options.TLSConfig).NotTo(BeIdenticalTo(existing))
+		Expect(options.TLSConfig.ServerName).To(Equal("example.com"))
+		Expect(options.TLSConfig.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(existing.MinVersion).To(BeZero())
+	})
+})
+
+var _ = Describe("CreateHttpServer", func() {
+	It("skips TLS handshake errors in the error log", func() {
+		buf := &bytes.Buffer{}
+		server := libhttp.CreateHttpServer(
+			":0",
+			http.NotFoundHandler(),
+			libhttp.ServerOptions{ErrorLogWriter: buf},
+		)
+		Expect(server.ErrorLog).NotTo(BeNil())
+		server.ErrorLog.Printf("http: TLS handshake error from 127.0.0.1:1234: EOF")
+		server.ErrorLog.Printf("http: Accept error: boom")
+		Expect(buf.String()).NotTo(ContainSubstring("TLS handshake error"))
+		Expect(buf.String()).To(ContainSubstring("http: Accept error: boom"))
+	})
+})
+
 func freePort() (int, error) {
 	addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
 	if err != nil {
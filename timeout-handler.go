@@ -0,0 +1,159 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// NewTimeoutHandler runs the given handler with a context timeout of duration.
+// If the handler does not complete in time a JSON 503 with ErrorCodeTimeout is written
+// and everything the handler writes afterward is discarded. If the client cancels the request nothing is written.
+// The response is buffered until the handler completes or flushes. After a flush the response is
+// streamed and a timeout only cancels the handler, because the status code was already sent.
+func NewTimeoutHandler(next http.Handler, duration time.Duration) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), duration)
+		defer cancel()
+
+		tw := &timeoutWriter{
+			ctx:    ctx,
+			resp:   resp,
+			header: make(http.Header),
+		}
+		done := make(chan struct{})
+		panicChan := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- p
+				}
+			}()
+			next.ServeHTTP(tw, req.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case p := <-panicChan:
+			panic(p)
+		case <-done:
+			tw.mux.Lock()
+			defer tw.mux.Unlock()
+			tw.writeBuffered()
+		case <-ctx.Done():
+			tw.mux.Lock()
+			defer tw.mux.Unlock()
+			tw.timedOut = true
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				glog.V(2).Infof("handle %s request to %s canceled by client", req.Method, req.URL.Path)
+				return
+			}
+			glog.V(1).Infof("handle %s request to %s timed out after %v", req.Method, req.URL.Path, duration)
+			if tw.flushed {
+				return
+			}
+			if err := SendJSONResponse(
+				req.Context(),
+				resp,
+				ErrorResponse{
+					Error: ErrorDetails{
+						Code:    ErrorCodeTimeout,
+						Message: "request timed out",
+					},
+				},
+				http.StatusServiceUnavailable,
+			); err != nil {
+				glog.Warningf("send error response failed: %v", err)
+			}
+		}
+	})
+}
+
+type timeoutWriter struct {
+	mux      sync.Mutex
+	ctx      context.Context
+	resp     http.ResponseWriter
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+	// flushed is true after the buffered response was written to resp, writes go to resp directly afterward
+	flushed bool
+}
+
+// writeBuffered writes header, status code and buffered body to resp. The caller must hold mux.
+func (t *timeoutWriter) writeBuffered() {
+	if t.flushed {
+		return
+	}
+	t.flushed = true
+	for key, values := range t.header {
+		t.resp.Header()[key] = values
+	}
+	if t.code == 0 {
+		t.code = http.StatusOK
+	}
+	t.resp.WriteHeader(t.code)
+	_, _ = t.resp.Write(t.buf.Bytes())
+	t.buf.Reset()
+}
+
+// expired reports whether the handler context is done and further writes must be discarded. The caller must hold mux.
+func (t *timeoutWriter) expired() bool {
+	return t.timedOut || t.ctx.Err() != nil
+}
+
+func (t *timeoutWriter) Header() http.Header {
+	return t.header
+}
+
+func (t *timeoutWriter) Write(p []byte) (int, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	if t.flushed {
+		return t.resp.Write(p)
+	}
+	if t.code == 0 {
+		t.code = http.StatusOK
+	}
+	return t.buf.Write(p)
+}
+
+func (t *timeoutWriter) WriteHeader(code int) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.expired() || t.code != 0 {
+		return
+	}
+	t.code = code
+}
+
+// Flush writes the buffered response and flushes it to the client if the underlying writer supports it.
+func (t *timeoutWriter) Flush() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.expired() {
+		return
+	}
+	t.writeBuffered()
+	if flusher, ok := t.resp.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (t *timeoutWriter) Unwrap() http.ResponseWriter {
+	return t.resp
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TimeoutHandler", func() {
+	var err error
+	var req *http.Request
+	var resp *httptest.ResponseRecorder
+	var handler http.Handler
+	var handlerCanceled chan struct{}
+	BeforeEach(func() {
+		req, err = http.NewRequest(http.MethodGet, "http://example.com", nil)
+		Expect(err).To(BeNil())
+		resp = httptest.NewRecorder()
+		handlerCanceled = make(chan struct{})
+	})
+	JustBeforeEach(func() {
+		libhttp.NewTimeoutHandler(handler, 50*time.Millisecond).ServeHTTP(resp, req)
+	})
+	Context("slow handler", func() {
+		BeforeEach(func() {
+			canceled := handlerCanceled
+			handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				<-req.Context().Done()
+				close(canceled)
+				fmt.Fprint(resp, "too late")
+			})
+		})
+		It("returns status code 503", func() {
+			Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+		It("returns timeout error response", func() {
+			var errorResponse libhttp.ErrorResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+			Expect(errorResponse.Error.Code).To(Equal(libhttp.ErrorCodeTimeout))
+		})
+		It("cancels handler context", func() {
+			Eventually(handlerCanceled).Should(BeClosed())
+		})
+	})
+	Context("request canceled by client", func() {
+		BeforeEach(func() {
+			ctx, cancel := context.WithCancel(req.Context())
+			req = req.WithContext(ctx)
+			handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				cancel()
+				<-req.Context().Done()
+				fmt.Fprint(resp, "too late")
+			})
+		})
+		It("writes nothing", func() {
+			Expect(resp.Body.Len()).To(Equal(0))
+			Expect(resp.Header().Get(libhttp.ContentTypeHeaderName)).To(BeEmpty())
+		})
+	})
+	Context("fast handler", func() {
+		BeforeEach(func() {
+			handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				resp.Header().Set("X-Banana", "yes")
+				resp.WriteHeader(http.StatusCreated)
+				fmt.Fprint(resp, "ok")
+			})
+		})
+		It("returns status code of handler", func() {
+			Expect(resp.Code).To(Equal(http.StatusCreated))
+		})
+		It("returns header of handler", func() {
+			Expect(resp.Header().Get("X-Banana")).To(Equal("yes"))
+		})
+		It("returns body of handler", func() {
+			Expect(resp.Body.String()).To(Equal("ok"))
+		})
+	})
+	Context("flushing handler", func() {
+		var flushedBody chan string
+		BeforeEach(func() {
+			recorder := resp
+			flushed := make(chan string, 1)
+			flushedBody = flushed
+			handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, "first")
+				_ = http.NewResponseController(w).Flush()
+				flushed <- recorder.Body.String()
+				<-req.Context().Done()
+				fmt.Fprint(w, "too late")
+			})
+		})
+		It("streams body written before flush", func() {
+			Expect(<-flushedBody).To(Equal("first"))
+		})
+		It("flushes underlying writer", func() {
+			Expect(resp.Flushed).To(BeTrue())
+		})
+		It("keeps status code of handler", func() {
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+		It("keeps header of handler", func() {
+			Expect(resp.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+		})
+		It("discards writes after timeout", func() {
+			Expect(resp.Body.String()).To(Equal("first"))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// TrailingSlashMode defines how NewTrailingSlashHandler normalizes paths.
+type TrailingSlashMode int
+
+const (
+	// TrailingSlashRedirectStrip redirects /foo/ to /foo
+	TrailingSlashRedirectStrip TrailingSlashMode = iota
+	// TrailingSlashRedirectAppend redirects /foo to /foo/
+	TrailingSlashRedirectAppend
+	// TrailingSlashRewriteStrip passes /foo/ as /foo to the next handler
+	TrailingSlashRewriteStrip
+	// TrailingSlashRewriteAppend passes /foo as /foo/ to the next handler
+	TrailingSlashRewriteAppend
+)
+
+// NewTrailingSlashHandler normalizes the trailing slash of the request path,
+// so routes only need to be registered once.
+// Redirect modes respond 308 Permanent Redirect, which preserves method and body, and keep the query.
+// Rewrite modes change the path in place before calling next. The root path / is never changed.
+func NewTrailingSlashHandler(next http.Handler, mode TrailingSlashMode) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		path, ok := normalizeTrailingSlash(req.URL.Path, mode)
+		if !ok {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		switch mode {
+		case TrailingSlashRedirectStrip, TrailingSlashRedirectAppend:
+			// collapse leading slashes, //host would be a redirect to another host
+			location := *req.URL
+			location.Path = "/" + strings.TrimLeft(path, "/")
+			location.RawPath = ""
+			glog.V(3).Infof("redirect %s to %s", req.URL.Path, path)
+			http.Redirect(resp, req, location.RequestURI(), http.StatusPermanentRedirect)
+		default:
+			req = req.Clone(req.Context())
+			req.URL.Path = path
+			req.URL.RawPath = ""
+			next.ServeHTTP(resp, req)
+		}
+	})
+}
+
+// normalizeTrailingSlash returns the normalized path and false if the path is already normalized.
+func normalizeTrailingSlash(path string, mode TrailingSlashMode) (string, bool) {
+	if path == "" || path == "/" {
+		return path, false
+	}
+	switch mode {
+	case TrailingSlashRedirectAppend, TrailingSlashRewriteAppend:
+		if strings.HasSuffix(path, "/") {
+			return path, false
+		}
+		return path + "/", true
+	default:
+		trimmed := strings.TrimRight(path, "/")
+		if trimmed == path {
+			return path, false
+		}
+		if trimmed == "" {
+			trimmed = "/"
+		}
+		return trimmed, true
+	}
+}
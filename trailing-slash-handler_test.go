@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TrailingSlashHandler", func() {
+	var nextPath string
+	var nextCounter int
+	BeforeEach(func() {
+		nextPath = ""
+		nextCounter = 0
+	})
+	serve := func(mode libhttp.TrailingSlashMode, method string, target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		libhttp.NewTrailingSlashHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			nextCounter++
+			nextPath = req.URL.Path
+		}), mode).ServeHTTP(resp, httptest.NewRequest(method, target, nil))
+		return resp
+	}
+	DescribeTable("redirect",
+		func(mode libhttp.TrailingSlashMode, target string, expectedLocation string) {
+			resp := serve(mode, http.MethodPost, target)
+			Expect(resp.Code).To(Equal(http.StatusPermanentRedirect))
+			Expect(resp.Header().Get("Location")).To(Equal(expectedLocation))
+			Expect(nextCounter).To(Equal(0))
+		},
+		Entry("strip", libhttp.TrailingSlashRedirectStrip, "/foo/", "/foo"),
+		Entry("strip with query", libhttp.TrailingSlashRedirectStrip, "/foo/?a=b", "/foo?a=b"),
+		Entry("append", libhttp.TrailingSlashRedirectAppend, "/foo", "/foo/"),
+		Entry("leading slashes", libhttp.TrailingSlashRedirectStrip, "//evil.example.com/", "/evil.example.com"),
+	)
+	DescribeTable("rewrite",
+		func(mode libhttp.TrailingSlashMode, target string, expectedPath string) {
+			resp := serve(mode, http.MethodGet, target)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(nextCounter).To(Equal(1))
+			Expect(nextPath).To(Equal(expectedPath))
+		},
+		Entry("strip", libhttp.TrailingSlashRewriteStrip, "/foo/", "/foo"),
+		Entry("strip multiple", libhttp.TrailingSlashRewriteStrip, "/foo//", "/foo"),
+		Entry("append", libhttp.TrailingSlashRewriteAppend, "/foo", "/foo/"),
+		Entry("already normalized", libhttp.TrailingSlashRewriteStrip, "/foo", "/foo"),
+	)
+	DescribeTable("keeps the root path",
+		func(mode libhttp.TrailingSlashMode) {
+			resp := serve(mode, http.MethodGet, "/")
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(nextCounter).To(Equal(1))
+			Expect(nextPath).To(Equal("/"))
+		},
+		Entry("redirect strip", libhttp.TrailingSlashRedirectStrip),
+		Entry("redirect append", libhttp.TrailingSlashRedirectAppend),
+		Entry("rewrite strip", libhttp.TrailingSlashRewriteStrip),
+		Entry("rewrite append", libhttp.TrailingSlashRewriteAppend),
+	)
+	It("does not redirect normalized paths", func() {
+		resp := serve(libhttp.TrailingSlashRedirectStrip, http.MethodGet, "/foo")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(nextCounter).To(Equal(1))
+	})
+})
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ValidationError accumulates invalid fields to report them all at once.
+type ValidationError struct {
+	fields []validationField
+}
+
+type validationField struct {
+	field   string
+	message string
+}
+
+// Add records message for the given field.
+func (v *ValidationError) Add(field string, message string) {
+	v.fields = append(v.fields, validationField{
+		field:   field,
+		message: message,
+	})
+}
+
+// Err returns nil if no field was added, otherwise an error with ErrorCodeValidation,
+// status 400 and the messages per field under "fields" in the details.
+func (v *ValidationError) Err() error {
+	return v.errWithDetails(nil)
+}
+
+// errWithDetails is like Err but adds details next to "fields", e.g. the allowed values of an enum.
+func (v *ValidationError) errWithDetails(details map[string]any) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(v.fields))
+	messages := make([]string, 0, len(v.fields))
+	for _, f := range v.fields {
+		if existing, ok := fields[f.field]; ok {
+			fields[f.field] = fmt.Sprintf("%s; %s", existing, f.message)
+		} else {
+			fields[f.field] = f.message
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", f.field, f.message))
+	}
+	if details == nil {
+		details = make(map[string]any, 1)
+	}
+	details["fields"] = fields
+	return &detailsError{
+		codeError: codeError{
+			err:        fmt.Errorf("validation failed: %s", strings.Join(messages, ", ")),
+			code:       ErrorCodeValidation,
+			statusCode: http.StatusBadRequest,
+		},
+		details: details,
+	}
+}
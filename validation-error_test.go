@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidationError", func() {
+	var validationError libhttp.ValidationError
+	BeforeEach(func() {
+		validationError = libhttp.ValidationError{}
+	})
+	It("returns nil without fields", func() {
+		Expect(validationError.Err()).To(BeNil())
+	})
+	Context("with three fields", func() {
+		var err error
+		BeforeEach(func() {
+			validationError.Add("name", "required")
+			validationError.Add("email", "invalid format")
+			validationError.Add("age", "must be positive")
+			err = validationError.Err()
+		})
+		It("returns error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("validation failed: name: required, email: invalid format, age: must be positive"))
+		})
+		It("returns validation code and status code", func() {
+			var errorWithCode libhttp.ErrorWithCode
+			Expect(errors.As(err, &errorWithCode)).To(BeTrue())
+			Expect(errorWithCode.Code()).To(Equal(libhttp.ErrorCodeValidation))
+			var errorWithStatusCode libhttp.ErrorWithStatusCode
+			Expect(errors.As(err, &errorWithStatusCode)).To(BeTrue())
+			Expect(errorWithStatusCode.StatusCode()).To(Equal(http.StatusBadRequest))
+		})
+		It("serializes fields into details", func() {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+			libhttp.NewJSONErrorHandler(libhttp.WithErrorFunc(func(ctx context.Context, resp http.ResponseWriter, req *http.Request) error {
+				return err
+			})).ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+			var errorResponse libhttp.ErrorResponse
+			Expect(json.NewDecoder(resp.Body).Decode(&errorResponse)).To(Succeed())
+			Expect(errorResponse.Error.Details).To(Equal(map[string]any{
+				"fields": map[string]any{
+					"name":  "required",
+					"email": "invalid format",
+					"age":   "must be positive",
+				},
+			}))
+		})
+	})
+})